@@ -2,62 +2,117 @@ package hotelbyte
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"net/http"
 
+	"github.com/bytedance/sonic"
+
 	"github.com/hotelbyte-com/sdk-go/protocol"
 	"github.com/hotelbyte-com/sdk-go/protocol/types"
 )
 
-// Authenticate performs user authentication
-func (s *Client) Authenticate(ctx context.Context) error {
+// Authenticate performs user authentication.
+// The TokenStore, if configured, is consulted before requesting a new ticket.
+// It is safe for concurrent use: goroutines arriving while a ticket request is in flight share its result.
+func (s *Client) Authenticate(ctx context.Context) (err error) {
+	ctx, call := s.beginCall(ctx, "Authenticate", protocol.PathAuthTicket)
+	defer func() { call.end(err) }()
+
 	// 如果 token 存在且未过期（提前 5 分钟刷新），直接返回
-	if s.token != "" && time.Now().Before(s.tokenExpiry.Add(-5*time.Minute)) {
-		return nil
+	_, err = s.auth.ensure(ctx)
+	return err
+}
+
+// doAuthorized sends the request with the current ticket. If the server rejects the ticket,
+// it is invalidated and re-acquired, and the request is replayed exactly once.
+func (s *Client) doAuthorized(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, error) {
+	token, err := s.auth.ensure(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
+	req = withTestFlags(req, s.config.TestFlags)
+	resp, err := s.transport.Do(ctx, withAuthorization(req, token))
+	if err != nil || !s.isAuthFailure(resp) {
+		return resp, err
+	}
+
+	// The ticket was revoked or expired early
+	s.auth.invalidate(ctx, token)
+	if token, err = s.auth.refresh(ctx, token); err != nil {
+		return nil, fmt.Errorf("re-authentication failed: %w", err)
+	}
+	return s.transport.Do(ctx, withAuthorization(req, token))
+}
+
+// isAuthFailure reports whether the server rejected the ticket
+func (s *Client) isAuthFailure(resp *types.HttpResponse) bool {
+	codes := s.config.AuthConfig.ErrorCodes
+	var bizErr types.BizError
+	if len(codes) > 0 && len(resp.Body) > 0 {
+		// An unreadable body has no code
+		_ = sonic.Unmarshal(resp.Body, &bizErr)
+	}
+	return isUnauthorized(resp.StatusCode, bizErr.Code, codes)
+}
+
+// withAuthorization returns a copy of req carrying the ticket, leaving req untouched for a replay
+func withAuthorization(req *types.HttpRequest, token string) *types.HttpRequest {
+	r := *req
+	r.Headers = make(map[string]string, len(req.Headers)+1)
+	for k, v := range req.Headers {
+		r.Headers[k] = v
+	}
+	r.Headers["Authorization"] = "Bearer " + token
+	return &r
+}
+
+// fetchTicket requests a new ticket from the server
+func (s *Client) fetchTicket(ctx context.Context) (_ string, _ time.Time, err error) {
+	// A child span of the call needing the ticket, e.g. Authenticate, which is metered by itself
+	ctx, span := s.startSpan(ctx, "fetchTicket", protocol.PathAuthTicket)
+	defer func() { endSpan(span, err) }()
 
 	// Build authentication request
 	req := &protocol.AuthReq{
 		AppKey:    s.config.Credentials.AppKey,
 		AppSecret: s.config.Credentials.AppSecret,
-		TTL:       24 * 3600,
+		TTL:       int64(s.config.AuthConfig.TTL / time.Second),
 	}
 
 	httpReq := &Request{
 		Method: http.MethodPost,
-		Path:   "/api/auth/ticket",
+		Path:   protocol.PathAuthTicket,
 		Body:   req,
+
+		ResponseType: (*types.Response[protocol.AuthResp])(nil),
 	}
 	resp, err := s.transport.Do(ctx, httpReq)
 	if err != nil {
-		// On transport error, try fallback path if available
-		return err
+		return "", time.Time{}, err
 	}
 
-	r, err := types.NewResponseData[protocol.AuthResp](resp)
+	r, err := decodeResponse[protocol.AuthResp](protocol.PathAuthTicket, resp, s.config)
 	if err != nil {
-		return err
+		return "", time.Time{}, err
 	}
 
 	// 保存 token 和过期时间
-	s.token = r.Ticket
-	s.tokenExpiry = time.Now().Add(time.Duration(req.TTL) * time.Second)
-	return nil
+	return r.Ticket, time.Now().Add(time.Duration(req.TTL) * time.Second), nil
 }
 
 // GetToken returns the current authentication token
 func (s *Client) GetToken() string {
-	return s.token
+	return s.auth.Token()
 }
 
-// RefreshToken refreshes the authentication token
+// RefreshToken refreshes the authentication token.
+// A ticket renewed by another client sharing the TokenStore is adopted instead of requesting a new one.
 func (s *Client) RefreshToken(ctx context.Context) error {
-	// Clear current token and expiry to force re-authentication
-	s.token = ""
-	s.tokenExpiry = time.Time{}
-	// Re-authenticate
-	return s.Authenticate(ctx)
+	// Force re-authentication, joining any refresh already in flight
+	_, err := s.auth.refresh(ctx, s.auth.Token())
+	return err
 }
 
 // GetAuthToken returns the current token (alias for GetToken for backward compatibility)
@@ -67,8 +122,9 @@ func (s *Client) GetAuthToken() string {
 
 // GetAuthorizationHeader returns the authorization header value
 func (s *Client) GetAuthorizationHeader() string {
-	if s.token == "" {
+	token := s.GetToken()
+	if token == "" {
 		return ""
 	}
-	return "Bearer " + s.token
+	return "Bearer " + token
 }
@@ -0,0 +1,224 @@
+package hotelbyte
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// tokenRefreshWindow is how long before expiry a ticket is considered stale
+	tokenRefreshWindow = 5 * time.Minute
+	// backgroundRefreshLead is how long before the refresh window the background goroutine renews the ticket
+	backgroundRefreshLead = time.Minute
+	// backgroundRetryDelay is the wait before the background goroutine retries a failed renewal
+	backgroundRetryDelay = 30 * time.Second
+)
+
+// ticketFetcher requests a new ticket from the server
+type ticketFetcher func(ctx context.Context) (ticket string, expiry time.Time, err error)
+
+// authManager guards the ticket and collapses concurrent refreshes into a single in-flight call
+type authManager struct {
+	fetch ticketFetcher
+	store TokenStore // optional, consulted before fetching
+	key   string     // store key, the AppKey
+
+	mu       sync.RWMutex
+	token    string
+	expiry   time.Time
+	inflight *authCall
+
+	// ctx is cancelled on close and aborts any in-flight refresh
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// authCall is a refresh shared by every goroutine that asked for it while it was running
+type authCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+func newAuthManager(fetch ticketFetcher, store TokenStore, key string) *authManager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &authManager{
+		fetch:  fetch,
+		store:  store,
+		key:    key,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Token returns the cached ticket, which may be empty
+func (m *authManager) Token() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.token
+}
+
+func (m *authManager) validLocked(now time.Time) bool {
+	return m.token != "" && now.Before(m.expiry.Add(-tokenRefreshWindow))
+}
+
+// ensure returns a valid ticket, refreshing it when missing or about to expire
+func (m *authManager) ensure(ctx context.Context) (string, error) {
+	m.mu.RLock()
+	token, valid := m.token, m.validLocked(time.Now())
+	m.mu.RUnlock()
+	if valid {
+		return token, nil
+	}
+	return m.refresh(ctx, "")
+}
+
+// refresh returns a valid ticket other than reject, starting a new ticket request or joining the one
+// already in flight. Pass the current ticket as reject to force a renewal; it is not reused even if
+// the store still holds it.
+func (m *authManager) refresh(ctx context.Context, reject string) (string, error) {
+	m.mu.Lock()
+	if m.validLocked(time.Now()) && m.token != reject {
+		token := m.token
+		m.mu.Unlock()
+		return token, nil
+	}
+	call := m.inflight
+	if call == nil {
+		call = &authCall{done: make(chan struct{})}
+		m.inflight = call
+		go m.doRefresh(ctx, call, reject)
+	}
+	m.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// doRefresh runs the shared request. It is detached from the caller's cancellation so that one
+// caller giving up does not fail everybody else waiting on the same call; close still aborts it.
+func (m *authManager) doRefresh(ctx context.Context, call *authCall, reject string) {
+	rctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stop := context.AfterFunc(m.ctx, cancel)
+	defer stop()
+
+	var (
+		ticket string
+		expiry time.Time
+		err    error
+	)
+	if token := m.load(rctx, reject); token != nil {
+		ticket, expiry = token.Ticket, token.Expiry
+	} else {
+		ticket, expiry, err = m.fetch(rctx)
+		if err == nil && m.store != nil {
+			// The ticket is usable even if it can't be shared, so a store failure is not fatal
+			_ = m.store.Put(rctx, m.key, &Token{Ticket: ticket, Expiry: expiry})
+		}
+	}
+
+	m.mu.Lock()
+	if err == nil {
+		m.token = ticket
+		m.expiry = expiry
+	}
+	call.token = ticket
+	call.err = err
+	m.inflight = nil
+	m.mu.Unlock()
+	close(call.done)
+}
+
+// load returns a still valid ticket from the store, other than reject, or nil
+func (m *authManager) load(ctx context.Context, reject string) *Token {
+	if m.store == nil {
+		return nil
+	}
+	token, err := m.store.Get(ctx, m.key)
+	if err != nil || token == nil || token.Ticket == "" || token.Ticket == reject {
+		return nil
+	}
+	if !time.Now().Before(token.Expiry.Add(-tokenRefreshWindow)) {
+		return nil
+	}
+	return token
+}
+
+// invalidate drops a ticket rejected by the server, from memory and from the store.
+// A newer ticket, renewed meanwhile by another goroutine or process, is kept.
+func (m *authManager) invalidate(ctx context.Context, stale string) {
+	m.mu.Lock()
+	if m.token == stale {
+		m.token = ""
+		m.expiry = time.Time{}
+	}
+	m.mu.Unlock()
+
+	if m.store == nil {
+		return
+	}
+	if token, err := m.store.Get(ctx, m.key); err == nil && token != nil && token.Ticket == stale {
+		_ = m.store.Invalidate(ctx, m.key)
+	}
+}
+
+// nextRenewal returns how long the background goroutine should wait before renewing the ticket
+func (m *authManager) nextRenewal() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.token == "" {
+		return 0
+	}
+	d := time.Until(m.expiry.Add(-tokenRefreshWindow - backgroundRefreshLead))
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// startBackground launches a goroutine that renews the ticket before it enters the refresh window
+func (m *authManager) startBackground() {
+	m.done = make(chan struct{})
+	go m.run()
+}
+
+func (m *authManager) run() {
+	defer close(m.done)
+
+	timer := time.NewTimer(m.nextRenewal())
+	defer timer.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		delay := backgroundRetryDelay
+		if _, err := m.refresh(m.ctx, m.Token()); err == nil {
+			// a ticket shorter than the refresh window would otherwise be renewed in a tight loop
+			if d := m.nextRenewal(); d > 0 {
+				delay = d
+			}
+		}
+		timer.Reset(delay)
+	}
+}
+
+// close stops the background goroutine, if any, and aborts in-flight refreshes
+func (m *authManager) close() {
+	m.closeOnce.Do(func() {
+		m.cancel()
+		if m.done != nil {
+			<-m.done
+		}
+	})
+}
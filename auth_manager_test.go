@@ -0,0 +1,98 @@
+package hotelbyte
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAuthManagerSingleFlight(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	m := newAuthManager(func(ctx context.Context) (string, time.Time, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "ticket", time.Now().Add(time.Hour), nil
+	}, nil, "")
+	defer m.close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.ensure(context.Background()); err != nil {
+				t.Errorf("ensure failed: %v", err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected 1 ticket request, got %d", n)
+	}
+	if m.Token() != "ticket" {
+		t.Errorf("Expected token 'ticket', got '%s'", m.Token())
+	}
+}
+
+func TestAuthManagerCallerCancelDoesNotAbortRefresh(t *testing.T) {
+	release := make(chan struct{})
+	m := newAuthManager(func(ctx context.Context) (string, time.Time, error) {
+		select {
+		case <-release:
+			return "ticket", time.Now().Add(time.Hour), nil
+		case <-ctx.Done():
+			return "", time.Time{}, ctx.Err()
+		}
+	}, nil, "")
+	defer m.close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := m.ensure(ctx)
+		errCh <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	close(release)
+	if _, err := m.ensure(context.Background()); err != nil {
+		t.Fatalf("ensure failed: %v", err)
+	}
+	if m.Token() != "ticket" {
+		t.Errorf("Expected token 'ticket', got '%s'", m.Token())
+	}
+}
+
+func TestAuthManagerBackgroundRefresh(t *testing.T) {
+	var calls int32
+	m := newAuthManager(func(ctx context.Context) (string, time.Time, error) {
+		atomic.AddInt32(&calls, 1)
+		// expiry already inside the refresh window, so every renewal is retried after backgroundRetryDelay
+		return "ticket", time.Now().Add(time.Minute), nil
+	}, nil, "")
+	m.startBackground()
+
+	deadline := time.Now().Add(time.Second)
+	for m.Token() == "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	m.close()
+
+	if m.Token() != "ticket" {
+		t.Errorf("Expected background goroutine to fetch a ticket")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected 1 ticket request, got %d", n)
+	}
+}
@@ -0,0 +1,61 @@
+package hotelbyte
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+func TestReauthenticateOnRejectedTicket(t *testing.T) {
+	var tickets, searches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/auth/ticket":
+			n := atomic.AddInt32(&tickets, 1)
+			fmt.Fprintf(w, `{"code":0,"data":{"ticket":"t%d"}}`, n)
+		case "/api/search/hotelList":
+			atomic.AddInt32(&searches, 1)
+			switch r.Header.Get("Authorization") {
+			case "Bearer t1":
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"code":401,"msg":"ticket expired"}`)
+			case "Bearer t2":
+				fmt.Fprint(w, `{"code":0,"data":{"basic":{"sessionId":"s1"}}}`)
+			default:
+				fmt.Fprint(w, `{"code":1001,"msg":"invalid ticket"}`)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"), WithRetryConfig(0, 0, 0), WithAuthErrorCodes(1001))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	resp, err := client.HotelList(context.Background(), &protocol.HotelListReq{})
+	if err != nil {
+		t.Fatalf("HotelList failed: %v", err)
+	}
+	if resp.Basic.SessionId != "s1" {
+		t.Errorf("Expected session id 's1', got '%s'", resp.Basic.SessionId)
+	}
+	if tickets != 2 || searches != 2 {
+		t.Errorf("Expected 2 ticket requests and 2 searches, got %d and %d", tickets, searches)
+	}
+
+	// A rejection by BizError code is replayed once too, then returned to the caller instead of looping
+	client.auth.invalidate(context.Background(), "t2")
+	if _, err := client.HotelList(context.Background(), &protocol.HotelListReq{}); err == nil {
+		t.Error("Expected error, but got nil")
+	}
+	if tickets != 4 || searches != 4 {
+		t.Errorf("Expected 4 ticket requests and 4 searches, got %d and %d", tickets, searches)
+	}
+}
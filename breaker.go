@@ -0,0 +1,269 @@
+package hotelbyte
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is matched by errors.Is when a call was rejected by an open circuit breaker
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError is returned when a call is rejected by the open circuit breaker of its endpoint
+type CircuitOpenError struct {
+	Endpoint  string
+	OpenUntil time.Time // when the breaker starts letting probes through
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s: %v until %s", e.Endpoint, ErrCircuitOpen, e.OpenUntil.Format(time.RFC3339))
+}
+
+// Is implement errors.Is
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets every call through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every call with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets a few probe calls through to decide whether to close again
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig represents circuit breaker configuration, applied to each endpoint separately.
+// Network errors and 5xx responses count as failures.
+type CircuitBreakerConfig struct {
+	ConsecutiveFailures int           // trips after this many failures in a row; 0 disables the rule
+	FailureRatio        float64       // trips when the failure ratio within Window reaches it; 0 disables the rule
+	MinRequests         int           // requests needed within Window before FailureRatio applies
+	Window              time.Duration // period over which FailureRatio is computed
+	OpenTimeout         time.Duration // how long the breaker stays open before probing
+	HalfOpenProbes      int           // probes let through when half-open; that many successes close the breaker
+}
+
+// DefaultCircuitBreakerConfig returns default circuit breaker configuration
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		ConsecutiveFailures: 5,
+		FailureRatio:        0.5,
+		MinRequests:         20,
+		Window:              30 * time.Second,
+		OpenTimeout:         30 * time.Second,
+		HalfOpenProbes:      1,
+	}
+}
+
+// Validate validates the configuration
+func (c CircuitBreakerConfig) Validate() error {
+	if c.ConsecutiveFailures <= 0 && c.FailureRatio <= 0 {
+		return fmt.Errorf("circuit breaker needs ConsecutiveFailures or FailureRatio")
+	}
+	if c.FailureRatio > 1 {
+		return fmt.Errorf("failure ratio must <= 1")
+	}
+	if c.FailureRatio > 0 && c.Window <= 0 {
+		return fmt.Errorf("failure ratio window must > 0")
+	}
+	if c.OpenTimeout <= 0 {
+		return fmt.Errorf("open timeout must > 0")
+	}
+	if c.HalfOpenProbes <= 0 {
+		return fmt.Errorf("half-open probes must > 0")
+	}
+	return nil
+}
+
+// isBreakerFailure reports whether the attempt counts as a failure of the backend
+func isBreakerFailure(statusCode int, err error) bool {
+	return err != nil || statusCode >= http.StatusInternalServerError
+}
+
+// circuitBreakers keeps one circuit breaker per endpoint
+type circuitBreakers struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// newCircuitBreakers returns nil if config is nil
+func newCircuitBreakers(config *CircuitBreakerConfig) *circuitBreakers {
+	if config == nil {
+		return nil
+	}
+	return &circuitBreakers{
+		config:   *config,
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+// get returns the circuit breaker of an endpoint, created on its first call
+func (c *circuitBreakers) get(endpoint string) *circuitBreaker {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.breakers[endpoint]
+	if !ok {
+		b = &circuitBreaker{config: &c.config, endpoint: endpoint}
+		c.breakers[endpoint] = b
+	}
+	return b
+}
+
+// lookup returns the circuit breaker of an endpoint, nil if it has not been called
+func (c *circuitBreakers) lookup(endpoint string) *circuitBreaker {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.breakers[endpoint]
+}
+
+// States returns the state of every endpoint that has been called
+func (c *circuitBreakers) States() map[string]CircuitState {
+	states := make(map[string]CircuitState)
+	if c == nil {
+		return states
+	}
+	c.mu.Lock()
+	breakers := make([]*circuitBreaker, 0, len(c.breakers))
+	for _, b := range c.breakers {
+		breakers = append(breakers, b)
+	}
+	c.mu.Unlock()
+	for _, b := range breakers {
+		states[b.endpoint] = b.State()
+	}
+	return states
+}
+
+// circuitBreaker tracks the health of one endpoint
+type circuitBreaker struct {
+	config   *CircuitBreakerConfig
+	endpoint string
+
+	mu          sync.Mutex
+	state       CircuitState
+	generation  uint64 // bumped on every state change, so late results of a previous state are ignored
+	consecutive int
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int // probes let through in the half-open state
+	successes   int // successful probes in the half-open state
+}
+
+// State returns the current state, moving from open to half-open once OpenTimeout has passed
+func (b *circuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advanceLocked(time.Now())
+	return b.state
+}
+
+func (b *circuitBreaker) advanceLocked(now time.Time) {
+	if b.state == CircuitOpen && !now.Before(b.openedAt.Add(b.config.OpenTimeout)) {
+		b.setStateLocked(CircuitHalfOpen, now)
+	}
+}
+
+func (b *circuitBreaker) setStateLocked(state CircuitState, now time.Time) {
+	b.state = state
+	b.generation++
+	b.consecutive, b.requests, b.failures = 0, 0, 0
+	b.probes, b.successes = 0, 0
+	b.windowStart = now
+	if state == CircuitOpen {
+		b.openedAt = now
+	}
+}
+
+// allow returns the generation the result must be recorded with, or a *CircuitOpenError
+func (b *circuitBreaker) allow(now time.Time) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advanceLocked(now)
+	switch b.state {
+	case CircuitOpen:
+		return 0, &CircuitOpenError{Endpoint: b.endpoint, OpenUntil: b.openedAt.Add(b.config.OpenTimeout)}
+	case CircuitHalfOpen:
+		if b.probes >= b.config.HalfOpenProbes {
+			return 0, &CircuitOpenError{Endpoint: b.endpoint, OpenUntil: now}
+		}
+		b.probes++
+	}
+	return b.generation, nil
+}
+
+// record counts the result of a call let through by allow
+func (b *circuitBreaker) record(generation uint64, failure bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation != b.generation {
+		return
+	}
+
+	switch b.state {
+	case CircuitClosed:
+		if b.config.Window > 0 && now.Sub(b.windowStart) >= b.config.Window {
+			b.windowStart = now
+			b.requests, b.failures = 0, 0
+		}
+		b.requests++
+		if !failure {
+			b.consecutive = 0
+			return
+		}
+		b.failures++
+		b.consecutive++
+		if (b.config.ConsecutiveFailures > 0 && b.consecutive >= b.config.ConsecutiveFailures) ||
+			(b.config.FailureRatio > 0 && b.requests >= b.config.MinRequests &&
+				float64(b.failures)/float64(b.requests) >= b.config.FailureRatio) {
+			b.setStateLocked(CircuitOpen, now)
+		}
+	case CircuitHalfOpen:
+		if failure {
+			b.setStateLocked(CircuitOpen, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.config.HalfOpenProbes {
+			b.setStateLocked(CircuitClosed, now)
+		}
+	}
+}
+
+// abandon releases a call let through by allow whose result says nothing about the backend,
+// e.g. because the caller gave up, so that a half-open breaker can send another probe
+func (b *circuitBreaker) abandon(generation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation == b.generation && b.state == CircuitHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
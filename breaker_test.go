@@ -0,0 +1,81 @@
+package hotelbyte
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerStates(t *testing.T) {
+	config := CircuitBreakerConfig{ConsecutiveFailures: 2, OpenTimeout: time.Minute, HalfOpenProbes: 1}
+	b := &circuitBreaker{config: &config, endpoint: "/x"}
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		gen, err := b.allow(now)
+		if err != nil {
+			t.Fatalf("allow failed: %v", err)
+		}
+		b.record(gen, true, now)
+	}
+	if _, err := b.allow(now); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+
+	// After OpenTimeout a single probe goes through
+	now = now.Add(time.Minute)
+	gen, err := b.allow(now)
+	if err != nil {
+		t.Fatalf("Expected a probe to be allowed, got %v", err)
+	}
+	if _, err := b.allow(now); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected a second probe to be rejected, got %v", err)
+	}
+	b.record(gen, false, now)
+	if b.state != CircuitClosed {
+		t.Errorf("Expected closed after a successful probe, got %v", b.state)
+	}
+}
+
+func TestCircuitBreakerFailsFast(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(
+		WithBaseURL(srv.URL),
+		WithCredentials("key", "secret"),
+		WithRetryConfig(5, time.Millisecond, time.Millisecond),
+		WithCircuitBreaker(CircuitBreakerConfig{ConsecutiveFailures: 3, OpenTimeout: time.Minute, HalfOpenProbes: 1}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/api/search/hotelList"})
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || openErr.Endpoint != "/api/search/hotelList" {
+		t.Fatalf("Expected *CircuitOpenError, got %v", err)
+	}
+	if hits != 3 {
+		t.Errorf("Expected retries to stop once the breaker tripped after 3 hits, got %d", hits)
+	}
+	if state := client.CircuitState("/api/search/hotelList"); state != CircuitOpen {
+		t.Errorf("Expected open, got %v", state)
+	}
+	if state := client.CircuitStates()["/api/search/hotelList"]; state != CircuitOpen {
+		t.Errorf("Expected open, got %v", state)
+	}
+	// Reading the state of an endpoint never called adds no breaker
+	if state := client.CircuitState("/api/other"); state != CircuitClosed || len(client.CircuitStates()) != 1 {
+		t.Errorf("Expected closed and one breaker, got %v, %v", state, client.CircuitStates())
+	}
+}
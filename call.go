@@ -0,0 +1,137 @@
+package hotelbyte
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// Call sends req to the endpoint at path and returns the data of the response envelope. The Client
+// methods are built on it, and it reaches endpoints the SDK has no method for yet:
+//
+//	resp, err := hotelbyte.Call[NewReq, NewResp](ctx, client, http.MethodPost, "/api/new/endpoint", req)
+//
+// Request headers are taken from the `api.header` tags of Req, and req is the body, with its
+// defaults applied and validated first, see protocol.ApplyDefaults and protocol.Validate. The ticket,
+// call options, retries, logging, tracing and metrics apply as to any Client method, and failures are
+// reported as *APIError, *TransportError or *protocol.ValidationError, or as *types.ContractError
+// with WithContractCheck(types.ContractStrict) on the endpoints of class RetryIdempotent.
+func Call[Req, Resp any](ctx context.Context, c *Client, method, path string, req *Req, opts ...CallOption) (*Resp, error) {
+	return call[Req, Resp](ctx, c, endpoint{name: path, method: method, path: path}, req, opts)
+}
+
+// endpoint describes the calls to an endpoint
+type endpoint struct {
+	name   string // name of the span and of errors, e.g. "HotelList"
+	method string
+	path   string
+	attrs  func() []attribute.KeyValue // span attributes of the request, read only if it is not nil
+}
+
+func call[Req, Resp any](ctx context.Context, c *Client, e endpoint, req *Req, opts []CallOption) (_ *Resp, err error) {
+	var attrs []attribute.KeyValue
+	if req != nil && e.attrs != nil {
+		attrs = e.attrs()
+	}
+	// Requests rejected before sending are traced and metered too
+	ctx, scope := c.beginCall(ctx, e.name, e.path, attrs...)
+	defer func() { scope.end(err) }()
+
+	if req != nil {
+		// Defaults are applied to a deep copy, leaving the request of the caller as is
+		r := deepCopy(reflect.ValueOf(req)).Interface().(*Req)
+		if err := protocol.ApplyDefaults(r); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.name, err)
+		}
+		if err := protocol.Validate(r); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.name, err)
+		}
+		req = r
+	}
+
+	httpReq := &types.HttpRequest{
+		Method:  e.method,
+		Path:    e.path,
+		Headers: requestHeaders(req),
+
+		ResponseType: (*types.Response[Resp])(nil),
+	}
+	if req != nil {
+		httpReq.Body = req
+	}
+
+	resp, err := c.send(ctx, httpReq, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", e.name, err)
+	}
+	if mode := c.config.ContractMode; mode != types.ContractOff {
+		if mode == types.ContractStrict && c.config.RetryConfig.classOf(e.path) != RetryIdempotent {
+			// The call may have changed the server state, e.g. a booking, so a success is never
+			// turned into an error: the violations are only reported
+			mode = types.ContractWarn
+		}
+		resp.Contract = &types.ContractCheck{Mode: mode, Report: func(violations []types.ContractViolation) {
+			if c.config.ContractReport != nil {
+				c.config.ContractReport(ctx, e.path, violations)
+			}
+			if c.config.Logging.enabled() {
+				c.config.Logging.Logger.Log(ctx, LevelWarn, "response contract violated",
+					Field{Key: "endpoint", Value: e.path}, Field{Key: "violations", Value: violations})
+			}
+		}}
+	}
+	return decodeResponse[Resp](e.path, resp, c.config)
+}
+
+// deepCopy returns a copy of v sharing no pointer, slice or map with it. Interfaces and unexported
+// fields, which ApplyDefaults does not set, are copied as they are.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := c.Field(i); f.CanSet() {
+				f.Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	}
+	return v
+}
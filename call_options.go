@@ -0,0 +1,144 @@
+package hotelbyte
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// CallConfig represents the configuration of one Client method call
+type CallConfig struct {
+	// Timeout bounds the call, authentication and retries included; 0 keeps the deadline of the context
+	Timeout time.Duration
+	// Headers are added to the request, overriding the ones derived from it
+	Headers map[string]string
+	// RetryPolicy overrides the retries of the endpoint; nil keeps RetryConfig
+	RetryPolicy *RetryPolicy
+	// ResponseMeta is filled once the call returns; nil skips it
+	ResponseMeta *ResponseMeta
+}
+
+// CallOption represents a call configuration option, passed to any Client method
+type CallOption func(*CallConfig) error
+
+// RetryPolicy tells how one call is retried
+type RetryPolicy struct {
+	Class      RetryClass
+	MaxRetries int
+}
+
+// WithCallTimeout sets the timeout of the call
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(c *CallConfig) error {
+		if timeout <= 0 {
+			return fmt.Errorf("timeout must > 0")
+		}
+		c.Timeout = timeout
+		return nil
+	}
+}
+
+// WithHeader adds a header to the request
+func WithHeader(key, value string) CallOption {
+	return func(c *CallConfig) error {
+		if key == "" {
+			return fmt.Errorf("empty header key")
+		}
+		if c.Headers == nil {
+			c.Headers = make(map[string]string)
+		}
+		c.Headers[http.CanonicalHeaderKey(key)] = value
+		return nil
+	}
+}
+
+// WithRequestID sets the Request-Id header identifying the request, see protocol.CommonHeader
+func WithRequestID(id string) CallOption {
+	return func(c *CallConfig) error {
+		if id == "" {
+			return fmt.Errorf("empty request id")
+		}
+		return WithHeader("Request-Id", id)(c)
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header, so that the server performs a repeated request once
+func WithIdempotencyKey(key string) CallOption {
+	return func(c *CallConfig) error {
+		if key == "" {
+			return fmt.Errorf("empty idempotency key")
+		}
+		return WithHeader("Idempotency-Key", key)(c)
+	}
+}
+
+// WithRetryPolicy sets how the call is retried, e.g. RetryPolicy{Class: RetryNever} to fail fast
+func WithRetryPolicy(policy RetryPolicy) CallOption {
+	return func(c *CallConfig) error {
+		if policy.MaxRetries < 0 {
+			return fmt.Errorf("max retries must >= 0")
+		}
+		c.RetryPolicy = &policy
+		return nil
+	}
+}
+
+// WithTestFlags sets the test flags of the call, overriding the ones of the request and the client.
+// Like any test flags, they are never sent to the production API.
+func WithTestFlags(flags protocol.TestFlags) CallOption {
+	return func(c *CallConfig) error {
+		if err := flags.Validate(); err != nil {
+			return fmt.Errorf("invalid test flags: %w", err)
+		}
+		return WithHeader("Test", flags.String())(c)
+	}
+}
+
+// send is the pipeline shared by the Client methods: the call options are applied, then the
+// request is sent with the current ticket
+func (s *Client) send(ctx context.Context, req *types.HttpRequest, opts []CallOption) (resp *types.HttpResponse, err error) {
+	start := time.Now()
+	var config CallConfig
+	for _, opt := range opts {
+		if err := opt(&config); err != nil {
+			return nil, fmt.Errorf("invalid call option: %w", err)
+		}
+	}
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+	if config.RetryPolicy != nil {
+		ctx = context.WithValue(ctx, retryPolicyKey{}, *config.RetryPolicy)
+	}
+	if len(config.Headers) > 0 {
+		r := *req
+		r.Headers = make(map[string]string, len(req.Headers)+len(config.Headers))
+		for k, v := range req.Headers {
+			r.Headers[k] = v
+		}
+		for k, v := range config.Headers {
+			r.Headers[k] = v
+		}
+		req = &r
+	}
+	if config.ResponseMeta != nil {
+		stats := &callStats{path: req.Path}
+		ctx = withCallStats(ctx, stats)
+		defer func() { config.ResponseMeta.fill(resp, stats, start) }()
+	}
+	return s.doAuthorized(ctx, req)
+}
+
+type retryPolicyKey struct{}
+
+// retryPolicyFromContext returns the RetryPolicy of the call, if any
+func retryPolicyFromContext(ctx context.Context) (RetryPolicy, bool) {
+	policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy)
+	return policy, ok
+}
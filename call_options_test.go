@@ -0,0 +1,92 @@
+package hotelbyte
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+func TestCallOptionHeaders(t *testing.T) {
+	var header http.Header
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		fmt.Fprint(w, `{"code":0,"data":{}}`)
+	})
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	req := &protocol.CheckAvailReq{RatePkgId: "pkg-1", SessionOption: protocol.SessionOption{SessionId: "session-1"}, TestOption: protocol.TestOption{Test: "scenario=soldOut"}}
+	_, err = client.CheckAvail(context.Background(), req,
+		WithRequestID("req-1"),
+		WithIdempotencyKey("idem-1"),
+		WithHeader("x-partner", "p1"),
+		WithTestFlags(protocol.TestFlags{Scenario: "slow"}),
+	)
+	if err != nil {
+		t.Fatalf("CheckAvail failed: %v", err)
+	}
+	for key, want := range map[string]string{
+		"Request-Id":      "req-1",
+		"Idempotency-Key": "idem-1",
+		"X-Partner":       "p1",
+		"Test":            "scenario=slow",
+		"Authorization":   "Bearer t1",
+	} {
+		if got := header.Get(key); got != want {
+			t.Errorf("Expected %s to be %q, got %q", key, want, got)
+		}
+	}
+
+	if _, err := client.CheckAvail(context.Background(), req, WithRequestID("")); err == nil {
+		t.Error("Expected an invalid call option to fail the call")
+	}
+}
+
+func TestCallOptionTimeoutAndRetryPolicy(t *testing.T) {
+	var searches int32
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case protocol.PathCheckAvail:
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+			fmt.Fprint(w, `{"code":0,"data":{}}`)
+		default:
+			atomic.AddInt32(&searches, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"), WithRetryConfig(3, time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	started := time.Now()
+	_, err = client.CheckAvail(ctx, &protocol.CheckAvailReq{RatePkgId: "pkg-1", SessionOption: protocol.SessionOption{SessionId: "session-1"}}, WithCallTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(started) > 500*time.Millisecond {
+		t.Errorf("Expected the call to time out, got %v after %v", err, time.Since(started))
+	}
+
+	_, _ = client.HotelList(ctx, &protocol.HotelListReq{}, WithRetryPolicy(RetryPolicy{Class: RetryIdempotent, MaxRetries: 1}))
+	if searches != 2 {
+		t.Errorf("Expected 2 attempts, got %d", searches)
+	}
+	_, _ = client.HotelList(ctx, &protocol.HotelListReq{}, WithRetryPolicy(RetryPolicy{Class: RetryNever}))
+	if searches != 3 {
+		t.Errorf("Expected 1 more attempt, got %d", searches-2)
+	}
+}
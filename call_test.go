@@ -0,0 +1,191 @@
+package hotelbyte
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+type promotionsReq struct {
+	HotelId int64 `json:"hotelId"`
+	protocol.CurrencyOption
+	Channel string `json:"-" api.header:"Channel"`
+}
+
+type promotionsResp struct {
+	Codes  []string              `json:"codes"`
+	Header protocol.CommonHeader `json:"header"`
+}
+
+// newTicketServer starts a server issuing the ticket "t1" and passing the other requests to handler
+func newTicketServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == protocol.PathAuthTicket {
+			fmt.Fprint(w, `{"code":0,"data":{"ticket":"t1"}}`)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCall(t *testing.T) {
+	var header http.Header
+	var body string
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/promotions":
+			header = r.Header.Clone()
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+			w.Header().Set("Trace-Id", "trace-1")
+			fmt.Fprint(w, `{"code":0,"data":{"codes":["SUMMER"]}}`)
+		default:
+			w.Header().Set("Trace-Id", "trace-2")
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	req := &promotionsReq{HotelId: 1, CurrencyOption: protocol.CurrencyOption{Currency: "EUR"}, Channel: "web"}
+	resp, err := Call[promotionsReq, promotionsResp](ctx, client, http.MethodPost, "/api/promotions", req, WithRequestID("req-1"))
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if len(resp.Codes) != 1 || resp.Header.TraceId != "trace-1" {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+	for key, want := range map[string]string{"Currency": "EUR", "Channel": "web", "Request-Id": "req-1", "Authorization": "Bearer t1"} {
+		if got := header.Get(key); got != want {
+			t.Errorf("Expected %s to be %q, got %q", key, want, got)
+		}
+	}
+	if body != `{"hotelId":1,"currency":"EUR"}` {
+		t.Errorf("Unexpected body %s", body)
+	}
+
+	_, err = Call[struct{}, promotionsResp](ctx, client, http.MethodGet, "/api/hotel/1", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Endpoint != "/api/hotel/1" || apiErr.TraceID != "trace-2" || !IsNotFound(err) {
+		t.Errorf("Expected a not found APIError, got %v", err)
+	}
+}
+
+func TestCallValidation(t *testing.T) {
+	var body string
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		fmt.Fprint(w, `{"code":0,"data":{}}`)
+	})
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	_, err = client.Book(ctx, &protocol.BookReq{CustomerReferenceNo: "ref-1"})
+	var validationErr *protocol.ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Fields) != 4 || body != "" {
+		t.Errorf("Expected a ValidationError of 4 fields and nothing sent, got %v", err)
+	}
+
+	req := &protocol.HotelListReq{}
+	if _, err := client.HotelList(ctx, req); err != nil {
+		t.Fatalf("HotelList failed: %v", err)
+	}
+	if !strings.Contains(body, `"pageSize":10`) || !strings.Contains(body, `"countryCode":"US"`) {
+		t.Errorf("Expected the defaults to be sent, got %s", body)
+	}
+	if req.PageSize != 0 || req.CheckIn != 0 {
+		t.Errorf("Expected the request of the caller to be left as is, got %+v", req)
+	}
+
+	// Down to the structs behind its pointers
+	type stayReq struct {
+		Stays []*protocol.CheckInOut `json:"stays"`
+	}
+	stays := &stayReq{Stays: []*protocol.CheckInOut{{}}}
+	if _, err := Call[stayReq, struct{}](ctx, client, http.MethodPost, "/api/stays", stays); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if !strings.Contains(body, `"checkIn":"20`) || stays.Stays[0].CheckIn != 0 {
+		t.Errorf("Expected the defaults sent and the stay of the caller left as is, got %s, %+v", body, stays.Stays[0])
+	}
+}
+
+func TestCallContractCheck(t *testing.T) {
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case protocol.PathBook:
+			fmt.Fprint(w, `{"code":0,"data":{"hotelOrder":{"supplierReferenceNo":"S1"},"newField":true}}`)
+		default:
+			fmt.Fprint(w, `{"code":0,"data":{"list":[{"id":"1","newField":true}],"basic":{}}}`)
+		}
+	})
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"),
+		WithLogger(NewSlogLogger(logger)), WithContractCheck(types.ContractWarn))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+	if _, err := client.HotelList(ctx, &protocol.HotelListReq{}); err != nil {
+		t.Fatalf("Expected the data in warn mode, got %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "response contract violated") || !strings.Contains(out, "data.list[0].newField") {
+		t.Errorf("Expected the violation to be logged, got %s", out)
+	}
+
+	reported := map[string][]types.ContractViolation{}
+	strict, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"), WithContractCheck(types.ContractStrict),
+		WithContractReport(func(_ context.Context, endpoint string, violations []types.ContractViolation) {
+			reported[endpoint] = violations
+		}))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer strict.Close()
+	_, err = strict.HotelList(ctx, &protocol.HotelListReq{})
+	var contractErr *types.ContractError
+	if !errors.As(err, &contractErr) || contractErr.Violations[0].Kind != types.ViolationUnknownField {
+		t.Errorf("Expected a ContractError, got %v", err)
+	}
+	// A booking is never failed once made
+	book, err := strict.Book(ctx, testBookReq())
+	if err != nil || book.HotelOrder == nil || book.HotelOrder.SupplierReferenceNo != "S1" {
+		t.Errorf("Expected the order of a strict Book, got %+v, %v", book, err)
+	}
+	if v := reported[protocol.PathBook]; len(v) == 0 || v[len(v)-1].Path != "data.newField" {
+		t.Errorf("Expected data.newField reported, got %v", reported)
+	}
+
+	if _, err := NewClient(WithContractCheck(types.ContractMode(9))); err == nil {
+		t.Error("Expected an unknown contract mode to be rejected")
+	}
+	if _, err := NewClient(WithContractReport(nil)); err == nil {
+		t.Error("Expected a nil contract report to be rejected")
+	}
+}
@@ -4,17 +4,24 @@ package hotelbyte
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"slices"
 	"time"
 
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
 	"github.com/hotelbyte-com/sdk-go/protocol/types"
 )
 
 // Client is the main HotelByte API client
 type Client struct {
-	config      *Config
-	transport   *Transport
-	token       string
-	tokenExpiry time.Time
+	config    *Config
+	transport *Transport
+	auth      *authManager
+	tracer    trace.Tracer
+	metrics   MetricsRecorder
 }
 
 func (s *Client) Key() string {
@@ -27,6 +34,34 @@ type Config struct {
 	Credentials Credentials
 	HTTPConfig  HTTPConfig
 	RetryConfig RetryConfig
+	AuthConfig  AuthConfig
+
+	RateLimitConfig RateLimitConfig
+	TracingConfig   TracingConfig
+	Logging         LoggingConfig
+	// Metrics receives request, retry, business error and booking outcome metrics; nil disables them
+	Metrics MetricsRecorder
+	// CircuitBreaker enables a circuit breaker per endpoint; nil disables it
+	CircuitBreaker *CircuitBreakerConfig
+	// TestFlags are sent with every request that carries none of its own. Test flags are never
+	// sent to ProductionBaseURL: such requests fail with ErrTestFlagsInProduction.
+	TestFlags protocol.TestFlags
+	// ContractMode checks the response payloads against the protocol types: types.ContractWarn logs
+	// the violations at LevelWarn, types.ContractStrict fails the calls with a *types.ContractError,
+	// except on the endpoints that are not of class RetryIdempotent, e.g. Book and Cancel, where the
+	// violations are logged as with types.ContractWarn and the data is returned
+	ContractMode types.ContractMode
+	// ContractReport receives the violations of the calls the contract check does not fail, logging
+	// or not; nil leaves them to the logger
+	ContractReport func(ctx context.Context, endpoint string, violations []types.ContractViolation)
+	// ErrorCodes maps BizError codes to the sentinel errors the calls failing with them match, e.g.
+	// 3002 to ErrPriceChanged; the codes of ErrUnauthorized are those of AuthConfig.ErrorCodes
+	ErrorCodes map[int32]error
+
+	// Middlewares wrap every request sent by the transport, the first one outermost
+	Middlewares []Middleware
+	// DisableDefaultMiddlewares removes DefaultMiddlewares, e.g. the request logging
+	DisableDefaultMiddlewares bool
 }
 
 // Credentials represents authentication credentials
@@ -41,14 +76,32 @@ type HTTPConfig struct {
 	MaxIdleConns    int
 	MaxConnsPerHost int
 	UserAgent       string
+	// Transport sends the HTTP requests instead of the pooled default, e.g. a record/replay transport
+	Transport http.RoundTripper
 }
 
 // RetryConfig represents retry configuration
 type RetryConfig struct {
 	MaxRetries    int
-	InitialDelay  time.Duration
-	MaxDelay      time.Duration
-	BackoffFactor float64
+	InitialDelay  time.Duration // wait before the first retry
+	MaxDelay      time.Duration // longest wait; a 429 asking for longer is returned without retrying
+	BackoffFactor float64       // the wait is multiplied by BackoffFactor after each retry
+	// Endpoints sets the RetryClass per path; paths not listed are RetryIdempotent
+	Endpoints map[string]RetryClass
+}
+
+// AuthConfig represents ticket management configuration
+type AuthConfig struct {
+	// TTL is the requested ticket lifetime
+	TTL time.Duration
+	// ErrorCodes are BizError codes meaning the ticket was rejected, in addition to HTTP 401 and 403.
+	// On such a response the ticket is re-acquired and the request replayed once.
+	ErrorCodes []int32
+	// Store shares tickets across clients, processes and restarts; nil keeps them in the client only
+	Store TokenStore
+	// BackgroundRefresh renews the ticket in a background goroutine before it is about to expire,
+	// so that API calls never wait on authentication. The goroutine stops on Close.
+	BackgroundRefresh bool
 }
 
 // NewClient creates a new HotelByte client
@@ -75,6 +128,16 @@ func NewClient(options ...ClientOption) (*Client, error) {
 	client := &Client{
 		config:    config,
 		transport: transport,
+		tracer:    config.TracingConfig.tracer(),
+		metrics:   config.Metrics,
+	}
+	if client.metrics == nil {
+		client.metrics = nopMetrics{}
+	}
+	client.auth = newAuthManager(client.fetchTicket, config.AuthConfig.Store, config.Credentials.AppKey)
+	transport.SetReconciler(protocol.PathBook, client.reconcileBook)
+	if config.AuthConfig.BackgroundRefresh {
+		client.auth.startBackground()
 	}
 
 	return client, nil
@@ -83,7 +146,7 @@ func NewClient(options ...ClientOption) (*Client, error) {
 // DefaultConfig returns default configuration
 func DefaultConfig() *Config {
 	return &Config{
-		BaseURL: "https://api.hotelbyte.com",
+		BaseURL: ProductionBaseURL,
 		HTTPConfig: HTTPConfig{
 			Timeout:         120 * time.Second,
 			MaxIdleConns:    100,
@@ -95,6 +158,20 @@ func DefaultConfig() *Config {
 			InitialDelay:  time.Second,
 			MaxDelay:      30 * time.Second,
 			BackoffFactor: 2.0,
+			Endpoints:     DefaultRetryEndpoints(),
+		},
+		AuthConfig: AuthConfig{
+			TTL: 24 * time.Hour,
+		},
+		RateLimitConfig: RateLimitConfig{
+			Priorities: DefaultRatePriorities(),
+		},
+		Logging: LoggingConfig{
+			Logger:       NopLogger{},
+			Level:        LevelDebug,
+			ErrorLevel:   LevelWarn,
+			Fields:       DefaultLogFields(),
+			MaxBodyBytes: 4096,
 		},
 	}
 }
@@ -109,6 +186,16 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("empty credentials")
 	}
 
+	if c.CircuitBreaker != nil {
+		if err := c.CircuitBreaker.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if !c.TestFlags.IsZero() && isProduction(c.BaseURL) {
+		return ErrTestFlagsInProduction
+	}
+
 	return nil
 }
 
@@ -151,18 +238,306 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithHTTPTransport sets the http.RoundTripper sending the requests
+func WithHTTPTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Config) error {
+		if transport == nil {
+			return fmt.Errorf("nil http transport")
+		}
+		c.HTTPConfig.Transport = transport
+		return nil
+	}
+}
+
 // WithRetryConfig sets the retry configuration
 func WithRetryConfig(maxRetries int, initialDelay, maxDelay time.Duration) ClientOption {
 	return func(c *Config) error {
 		if maxRetries < 0 {
 			return fmt.Errorf("invalid max retries")
 		}
-		c.RetryConfig = RetryConfig{
-			MaxRetries:    maxRetries,
-			InitialDelay:  initialDelay,
-			MaxDelay:      maxDelay,
-			BackoffFactor: 2.0,
+		c.RetryConfig.MaxRetries = maxRetries
+		c.RetryConfig.InitialDelay = initialDelay
+		c.RetryConfig.MaxDelay = maxDelay
+		return nil
+	}
+}
+
+// WithBackoffFactor sets the factor the retry wait is multiplied by after each retry
+func WithBackoffFactor(factor float64) ClientOption {
+	return func(c *Config) error {
+		if factor < 1 {
+			return fmt.Errorf("backoff factor must >= 1")
+		}
+		c.RetryConfig.BackoffFactor = factor
+		return nil
+	}
+}
+
+// WithEndpointRetryClass sets the RetryClass of an endpoint path
+func WithEndpointRetryClass(path string, class RetryClass) ClientOption {
+	return func(c *Config) error {
+		if path == "" {
+			return fmt.Errorf("empty path")
+		}
+		endpoints := make(map[string]RetryClass, len(c.RetryConfig.Endpoints)+1)
+		for k, v := range c.RetryConfig.Endpoints {
+			endpoints[k] = v
+		}
+		endpoints[path] = class
+		c.RetryConfig.Endpoints = endpoints
+		return nil
+	}
+}
+
+// WithRateLimit sets the client-side budget shared by every endpoint, e.g. the QPS of the AppKey
+func WithRateLimit(qps float64, burst int) ClientOption {
+	return func(c *Config) error {
+		if qps <= 0 {
+			return fmt.Errorf("qps must > 0")
+		}
+		c.RateLimitConfig.Account = &Budget{QPS: qps, Burst: burst}
+		return nil
+	}
+}
+
+// WithEndpointRateLimit sets the client-side budget of an endpoint path, applied in addition to WithRateLimit
+func WithEndpointRateLimit(path string, qps float64, burst int) ClientOption {
+	return func(c *Config) error {
+		if path == "" {
+			return fmt.Errorf("empty path")
+		}
+		if qps <= 0 {
+			return fmt.Errorf("qps must > 0")
+		}
+		endpoints := make(map[string]Budget, len(c.RateLimitConfig.Endpoints)+1)
+		for k, v := range c.RateLimitConfig.Endpoints {
+			endpoints[k] = v
+		}
+		endpoints[path] = Budget{QPS: qps, Burst: burst}
+		c.RateLimitConfig.Endpoints = endpoints
+		return nil
+	}
+}
+
+// WithEndpointPriority sets the priority of an endpoint path when waiting for a rate limit budget
+func WithEndpointPriority(path string, priority Priority) ClientOption {
+	return func(c *Config) error {
+		if path == "" {
+			return fmt.Errorf("empty path")
+		}
+		priorities := make(map[string]Priority, len(c.RateLimitConfig.Priorities)+1)
+		for k, v := range c.RateLimitConfig.Priorities {
+			priorities[k] = v
+		}
+		priorities[path] = priority
+		c.RateLimitConfig.Priorities = priorities
+		return nil
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker per endpoint, see DefaultCircuitBreakerConfig
+func WithCircuitBreaker(config CircuitBreakerConfig) ClientOption {
+	return func(c *Config) error {
+		if err := config.Validate(); err != nil {
+			return err
+		}
+		c.CircuitBreaker = &config
+		return nil
+	}
+}
+
+// WithContractCheck checks the response payloads against the protocol types, to catch API drift
+// such as unknown fields, changed types or missing required fields in staging, see types.ContractCheck.
+// types.ContractStrict never fails a Book or a Cancel, or a call to another endpoint that is not of
+// class RetryIdempotent, since its success may have changed the server state.
+func WithContractCheck(mode types.ContractMode) ClientOption {
+	return func(c *Config) error {
+		if mode < types.ContractOff || mode > types.ContractStrict {
+			return fmt.Errorf("unknown contract mode %d", mode)
+		}
+		c.ContractMode = mode
+		return nil
+	}
+}
+
+// WithContractReport passes the violations found by WithContractCheck without failing the calls to
+// report, e.g. to count them in metrics or to fail tests, even with logging disabled
+func WithContractReport(report func(ctx context.Context, endpoint string, violations []types.ContractViolation)) ClientOption {
+	return func(c *Config) error {
+		if report == nil {
+			return fmt.Errorf("contract report must not be nil")
+		}
+		c.ContractReport = report
+		return nil
+	}
+}
+
+// WithTracerProvider enables OpenTelemetry tracing: every Client method starts a span from the provider,
+// and the trace context is injected into outgoing headers
+func WithTracerProvider(provider trace.TracerProvider) ClientOption {
+	return func(c *Config) error {
+		if provider == nil {
+			return fmt.Errorf("nil tracer provider")
+		}
+		c.TracingConfig.TracerProvider = provider
+		return nil
+	}
+}
+
+// WithPropagator sets the propagator injecting the trace context into outgoing headers, W3C trace context by default
+func WithPropagator(propagator propagation.TextMapPropagator) ClientOption {
+	return func(c *Config) error {
+		if propagator == nil {
+			return fmt.Errorf("nil propagator")
 		}
+		c.TracingConfig.Propagator = propagator
+		return nil
+	}
+}
+
+// WithMetrics sets the recorder of request, retry, business error and booking outcome metrics
+func WithMetrics(recorder MetricsRecorder) ClientOption {
+	return func(c *Config) error {
+		if recorder == nil {
+			return fmt.Errorf("nil metrics recorder")
+		}
+		c.Metrics = recorder
+		return nil
+	}
+}
+
+// WithLogger sets the logger, e.g. NewSlogLogger(slog.Default())
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Config) error {
+		if logger == nil {
+			return fmt.Errorf("nil logger")
+		}
+		c.Logging.Logger = logger
+		return nil
+	}
+}
+
+// WithLogLevels sets the level of successful requests and of failed ones
+func WithLogLevels(level, errorLevel Level) ClientOption {
+	return func(c *Config) error {
+		c.Logging.Level = level
+		c.Logging.ErrorLevel = errorLevel
+		return nil
+	}
+}
+
+// WithLogFields sets the fields of request entries, see the LogField constants
+func WithLogFields(fields ...string) ClientOption {
+	return func(c *Config) error {
+		for _, f := range fields {
+			if !logFields[f] {
+				return fmt.Errorf("unknown log field %q", f)
+			}
+		}
+		c.Logging.Fields = append([]string(nil), fields...)
+		return nil
+	}
+}
+
+// WithBodyLogging logs request and response bodies truncated to maxBytes (0 means no limit), with
+// their PII fields redacted as set by WithRedaction
+func WithBodyLogging(maxBytes int) ClientOption {
+	return func(c *Config) error {
+		if maxBytes < 0 {
+			return fmt.Errorf("max body bytes must >= 0")
+		}
+		c.Logging.Bodies = true
+		c.Logging.MaxBodyBytes = maxBytes
+		return nil
+	}
+}
+
+// WithRedaction sets how the PII fields of logged bodies are redacted. The values of config.Keys
+// are masked too, e.g. DefaultRedactKeys for the bodies of unknown type.
+func WithRedaction(config RedactConfig) ClientOption {
+	return func(c *Config) error {
+		c.Logging.Redactor = NewRedactor(config)
+		return nil
+	}
+}
+
+// WithTokenTTL sets the requested ticket lifetime
+func WithTokenTTL(ttl time.Duration) ClientOption {
+	return func(c *Config) error {
+		if ttl <= tokenRefreshWindow {
+			return fmt.Errorf("token ttl must > %v", tokenRefreshWindow)
+		}
+		c.AuthConfig.TTL = ttl
+		return nil
+	}
+}
+
+// WithTokenStore sets the store used to share tickets across clients, processes and restarts
+func WithTokenStore(store TokenStore) ClientOption {
+	return func(c *Config) error {
+		if store == nil {
+			return fmt.Errorf("nil token store")
+		}
+		c.AuthConfig.Store = store
+		return nil
+	}
+}
+
+// WithAuthErrorCodes sets the BizError codes meaning the ticket was rejected by the server, besides
+// HTTP 401 and 403; none by default. Calls failing with them also match ErrUnauthorized.
+func WithAuthErrorCodes(codes ...int32) ClientOption {
+	return func(c *Config) error {
+		c.AuthConfig.ErrorCodes = append([]int32(nil), codes...)
+		return nil
+	}
+}
+
+// WithErrorCodes sets the BizError codes matching a sentinel error, e.g. WithErrorCodes(ErrPriceChanged, 3002).
+// The backend documents no codes yet, so none is set by default; the codes of ErrUnauthorized are set
+// with WithAuthErrorCodes.
+func WithErrorCodes(sentinel error, codes ...int32) ClientOption {
+	return func(c *Config) error {
+		if sentinel == ErrUnauthorized {
+			return fmt.Errorf("codes of ErrUnauthorized must be set with WithAuthErrorCodes")
+		}
+		if !slices.Contains(sentinels, sentinel) {
+			return fmt.Errorf("unknown sentinel error %v", sentinel)
+		}
+		if c.ErrorCodes == nil {
+			c.ErrorCodes = make(map[int32]error, len(codes))
+		}
+		for _, code := range codes {
+			c.ErrorCodes[code] = sentinel
+		}
+		return nil
+	}
+}
+
+// WithBackgroundTokenRefresh renews the ticket in a background goroutine before it expires
+func WithBackgroundTokenRefresh() ClientOption {
+	return func(c *Config) error {
+		c.AuthConfig.BackgroundRefresh = true
+		return nil
+	}
+}
+
+// WithMiddleware appends middlewares wrapping every request sent by the transport
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *Config) error {
+		for _, mw := range middlewares {
+			if mw == nil {
+				return fmt.Errorf("nil middleware")
+			}
+		}
+		c.Middlewares = append(c.Middlewares, middlewares...)
+		return nil
+	}
+}
+
+// WithoutDefaultMiddlewares removes the default middlewares, e.g. the request logging
+func WithoutDefaultMiddlewares() ClientOption {
+	return func(c *Config) error {
+		c.DisableDefaultMiddlewares = true
 		return nil
 	}
 }
@@ -172,8 +547,31 @@ func (s *Client) GetConfig() *Config {
 	return s.config
 }
 
+// RateLimitStatus returns the rate limit of the account as last reported by the server,
+// so that schedulers can back off before being throttled
+func (s *Client) RateLimitStatus() RateLimitStatus {
+	return s.transport.RateLimitStatus()
+}
+
+// CircuitState returns the circuit breaker state of an endpoint path.
+// It is CircuitClosed if no circuit breaker is configured or the endpoint has not been called.
+func (s *Client) CircuitState(path string) CircuitState {
+	if b := s.transport.breakers.lookup(path); b != nil {
+		return b.State()
+	}
+	return CircuitClosed
+}
+
+// CircuitStates returns the circuit breaker state of every endpoint called so far
+func (s *Client) CircuitStates() map[string]CircuitState {
+	return s.transport.CircuitStates()
+}
+
 // Close closes the client
 func (s *Client) Close() error {
+	if s.auth != nil {
+		s.auth.close()
+	}
 	if s.transport != nil {
 		return s.transport.Close()
 	}
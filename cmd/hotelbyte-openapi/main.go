@@ -0,0 +1,87 @@
+// Command hotelbyte-openapi writes the OpenAPI 3.1 document of the HotelByte API, generated from the
+// protocol package, so that clients in other languages are generated from the same source of truth:
+//
+//	go run github.com/hotelbyte-com/sdk-go/cmd/hotelbyte-openapi -o openapi.json
+//
+// With -jsonschema, it also writes the JSON Schema document of every protocol type to a directory,
+// one <Name>.json file each.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	hotelbyte "github.com/hotelbyte-com/sdk-go"
+	"github.com/hotelbyte-com/sdk-go/openapi"
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+func main() {
+	output := flag.String("o", "", "output file, stdout if empty")
+	version := flag.String("version", "1.0.0", "version of the API in the document")
+	jsonSchemaDir := flag.String("jsonschema", "", "directory of the JSON Schema documents, none if empty")
+	flag.Parse()
+
+	doc := openapi.Generate(openapi.Info{
+		Title:       "HotelByte API",
+		Version:     *version,
+		Description: "Hotel search, booking and order management. Every response is an envelope of a business error code and the data.",
+	}, protocol.Endpoints)
+	doc.Servers = []openapi.Server{
+		{URL: hotelbyte.ProductionBaseURL, Description: "Production"},
+		{URL: hotelbyte.SandboxBaseURL, Description: "Sandbox, accepting the Test header"},
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", *output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := write(w, doc); err != nil {
+		log.Fatalf("Failed to write the document: %v", err)
+	}
+	if *jsonSchemaDir != "" {
+		if err := writeJSONSchemas(*jsonSchemaDir); err != nil {
+			log.Fatalf("Failed to write the JSON Schema documents: %v", err)
+		}
+	}
+}
+
+func writeJSONSchemas(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, doc := range openapi.JSONSchemas(protocol.Endpoints) {
+		f, err := os.Create(filepath.Join(dir, name+".json"))
+		if err != nil {
+			return err
+		}
+		err = write(f, doc)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func write(w io.Writer, doc any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package hotelbyte
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// Sentinel errors of well-known failures, to be matched with errors.Is:
+//
+//	if errors.Is(err, hotelbyte.ErrPriceChanged) { ... }
+//
+// ErrUnauthorized matches HTTP 401 and 403 and the BizError codes of WithAuthErrorCodes, the
+// responses on which the ticket is re-acquired, and ErrNotFound matches HTTP 404. The backend
+// documents no business error codes yet, so the others match only the codes set with WithErrorCodes.
+var (
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrNotFound           = errors.New("not found")
+	ErrSessionExpired     = errors.New("session expired")
+	ErrRateUnavailable    = errors.New("rate unavailable")
+	ErrPriceChanged       = errors.New("price changed")
+	ErrDuplicateReference = errors.New("duplicate customer reference")
+	ErrInsufficientCredit = errors.New("insufficient credit")
+)
+
+// sentinels are the errors WithErrorCodes maps codes to
+var sentinels = []error{
+	ErrNotFound, ErrSessionExpired, ErrRateUnavailable, ErrPriceChanged, ErrDuplicateReference, ErrInsufficientCredit,
+}
+
+// APIError is returned when the server answered a call with an error: a business error code or
+// an HTTP error status. It unwraps to the *types.BizError of the response, if any.
+type APIError struct {
+	Endpoint   string // path of the endpoint, e.g. protocol.PathBook
+	StatusCode int    // HTTP status
+	Code       int32  // business error code, 0 if the response had none
+	Message    string
+	TraceID    string // Trace-Id response header, to quote to the support
+
+	sentinel error // sentinel error of the status or code, if any
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: status %d", e.Endpoint, e.StatusCode)
+	if e.Code != 0 {
+		fmt.Fprintf(&b, ", code %d", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.TraceID != "" {
+		fmt.Fprintf(&b, " (trace id %s)", e.TraceID)
+	}
+	return b.String()
+}
+
+// Unwrap returns the business error of the response, if any
+func (e *APIError) Unwrap() error {
+	if e.Code == 0 {
+		return nil
+	}
+	return types.NewBizErr(e.Code, e.Message)
+}
+
+// Is matches the sentinel error of the status or code; business errors also match by code through Unwrap
+func (e *APIError) Is(target error) bool {
+	return e.sentinel != nil && target == e.sentinel
+}
+
+// TransportError is returned when a request could not be sent or its response could not be read,
+// e.g. on a DNS failure, a refused connection or a timeout
+type TransportError struct {
+	Endpoint string
+	Err      error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("request failed: %v", e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether the same call may succeed if sent again later: transport failures,
+// except cancellations by the caller, 429 Too Many Requests, 5xx Server Errors and open circuits
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var transportErr *TransportError
+	return errors.As(err, &transportErr)
+}
+
+// IsNotFound reports whether the call failed with HTTP 404 Not Found or with one of the BizError
+// codes of ErrNotFound, see WithErrorCodes
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// isUnauthorized reports whether a response rejected the ticket: HTTP 401 or 403, or one of the
+// BizError codes of AuthConfig.ErrorCodes
+func isUnauthorized(statusCode int, code int32, authCodes []int32) bool {
+	return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden ||
+		code != 0 && slices.Contains(authCodes, code)
+}
+
+// sentinelOf returns the sentinel error of a response status and BizError code, nil if none
+func (c *Config) sentinelOf(statusCode int, code int32) error {
+	switch {
+	case isUnauthorized(statusCode, code, c.AuthConfig.ErrorCodes):
+		return ErrUnauthorized
+	case code != 0 && c.ErrorCodes[code] != nil:
+		return c.ErrorCodes[code]
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	}
+	return nil
+}
+
+// decodeResponse returns the data of a response, or an *APIError matching the sentinel errors of
+// config if the server answered with an error
+func decodeResponse[T any](endpoint string, resp *types.HttpResponse, config *Config) (*T, error) {
+	data, err := types.NewResponseData[T](resp)
+	if err == nil {
+		return data, nil
+	}
+	apiErr := &APIError{
+		Endpoint:   endpoint,
+		StatusCode: resp.StatusCode,
+		TraceID:    resp.Headers.Get("Trace-Id"),
+	}
+	if bizErr, ok := types.CastBizErr(err); ok && len(resp.Body) > 0 {
+		apiErr.Code = bizErr.Code
+		apiErr.Message = bizErr.Msg
+		apiErr.sentinel = config.sentinelOf(resp.StatusCode, bizErr.Code)
+		return nil, apiErr
+	}
+	if resp.StatusCode < http.StatusBadRequest && len(resp.Body) > 0 {
+		// A success status with an unreadable body
+		return nil, fmt.Errorf("%s: %w", endpoint, err)
+	}
+	apiErr.Message = http.StatusText(resp.StatusCode)
+	apiErr.sentinel = config.sentinelOf(resp.StatusCode, 0)
+	if len(resp.Body) == 0 {
+		apiErr.Message = "empty response"
+	}
+	return nil, apiErr
+}
@@ -0,0 +1,105 @@
+package hotelbyte
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+func TestAPIError(t *testing.T) {
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trace-Id", "trace-1")
+		switch r.URL.Path {
+		case protocol.PathBook:
+			fmt.Fprint(w, `{"code":3002,"msg":"price changed"}`)
+		case protocol.PathQueryOrders:
+			w.WriteHeader(http.StatusNotFound)
+		case protocol.PathCancel:
+			fmt.Fprint(w, `{"code":4001,"msg":"ticket revoked"}`)
+		case "/api/hotel/detail":
+			fmt.Fprint(w, `{"code":2001,"msg":"hotel not found"}`)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"), WithRetryConfig(0, 0, 0),
+		WithAuthErrorCodes(4001), WithErrorCodes(ErrPriceChanged, 3002), WithErrorCodes(ErrNotFound, 2001, 3004))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	_, err = client.Book(ctx, testBookReq())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Endpoint != protocol.PathBook || apiErr.Code != 3002 || apiErr.TraceID != "trace-1" {
+		t.Fatalf("Expected an APIError of Book, got %v", err)
+	}
+	if !errors.Is(err, ErrPriceChanged) || errors.Is(err, ErrRateUnavailable) || errors.Is(err, ErrUnauthorized) || IsRetryable(err) {
+		t.Errorf("Expected ErrPriceChanged only, not retryable, got %v", err)
+	}
+	if !errors.Is(err, types.NewBizErr(3002, "")) {
+		t.Errorf("Expected the business error to match by code, got %v", err)
+	}
+	if bizErr, ok := types.CastBizErr(err); !ok || bizErr.Code != 3002 {
+		t.Errorf("Expected the BizError to be unwrapped, got %v", err)
+	}
+
+	// The codes of WithAuthErrorCodes are those of ErrUnauthorized
+	_, err = client.Cancel(ctx, &protocol.CancelReq{CustomerReferenceNo: "ref-1", SupplierReferenceNo: "sup-1"})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+
+	_, err = client.QueryOrders(ctx, &protocol.QueryOrdersReq{})
+	if !IsNotFound(err) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+	_, err = Call[struct{}, struct{}](ctx, client, http.MethodPost, "/api/hotel/detail", nil)
+	if !IsNotFound(err) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the code of ErrNotFound to be not found, got %v", err)
+	}
+
+	_, err = client.HotelList(ctx, &protocol.HotelListReq{})
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || !IsRetryable(err) || IsNotFound(err) {
+		t.Errorf("Expected a retryable 503 APIError, got %v", err)
+	}
+}
+
+func TestWithErrorCodes(t *testing.T) {
+	for _, sentinel := range []error{ErrUnauthorized, errors.New("price changed"), nil} {
+		if _, err := NewClient(WithErrorCodes(sentinel, 3002)); err == nil {
+			t.Errorf("Expected WithErrorCodes(%v) to be rejected", sentinel)
+		}
+	}
+}
+
+func TestUnauthorizedAndTransportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"), WithRetryConfig(0, 0, 0))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	err = client.Authenticate(context.Background())
+	if !errors.Is(err, ErrUnauthorized) || IsRetryable(err) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+
+	srv.Close()
+	err = client.Authenticate(context.Background())
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) || transportErr.Endpoint != protocol.PathAuthTicket || !IsRetryable(err) {
+		t.Errorf("Expected a retryable TransportError, got %v", err)
+	}
+}
@@ -19,7 +19,7 @@ func main() {
 	// Initialize SDK client with credentials (use client options API)
 	client, err := hotelbyte.NewClient(
 		//hotelbyte.WithBaseURL("http://localhost:8080"),
-		hotelbyte.WithBaseURL("https://api-test.hotelbyte.com"),
+		hotelbyte.WithBaseURL(hotelbyte.SandboxBaseURL),
 		hotelbyte.WithCredentials("hotelbyte_api_demo", "hotelbyte_api_demo"),
 		hotelbyte.WithTimeout(120*time.Second),
 	)
@@ -27,16 +27,16 @@ func main() {
 		log.Fatalf("Failed to create client: %v", err)
 	}
 	defer client.Close()
-	testQueries := []string{
-		"",
-		"simulator=DISABLE&onlyAvailableSupplierHotels=true",
+	testFlags := []protocol.TestFlags{
+		{},
+		{Simulator: protocol.TestSimulatorDisable, OnlyAvailableSupplierHotels: true},
 	}
-	for _, tq := range testQueries {
-		run(client, tq)
+	for _, flags := range testFlags {
+		run(client, flags.Option())
 	}
 }
 
-func run(client *hotelbyte.Client, tq string) {
+func run(client *hotelbyte.Client, top protocol.TestOption) {
 	ctx := context.Background()
 
 	// Example 1: Search for hotels
@@ -69,9 +69,7 @@ func run(client *hotelbyte.Client, tq string) {
 			PageSize: 1000000,
 			PageNum:  1,
 		},
-		TestOption: protocol.TestOption{
-			Test: tq,
-		},
+		TestOption: top,
 	}
 
 	searchResp, err := client.HotelList(ctx, searchReq)
@@ -293,6 +291,7 @@ func handleRate(ctx context.Context, client *hotelbyte.Client, rate protocol.Roo
 
 	cancelResp, err := client.Cancel(ctx, &protocol.CancelReq{
 		CustomerReferenceNo: bookingReq.CustomerReferenceNo,
+		SupplierReferenceNo: bookingResp.HotelOrder.SupplierReferenceNo,
 		TestOption:          top,
 	})
 	if err != nil {
@@ -0,0 +1,38 @@
+package hotelbyte
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hotelbyte-com/sdk-go/internal/tags"
+)
+
+// requestHeaders returns the headers of a request body: the non-empty values of the fields tagged
+// `api.header:"Name"`, in the struct or in the structs it embeds or holds, such as CurrencyOption,
+// SessionOption, TestOption or CommonHeader. Slices and maps are not walked.
+func requestHeaders(v any) map[string]string {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := tags.Headers(val.Type())
+	if len(fields) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(fields))
+	for _, f := range fields {
+		fv, err := val.FieldByIndexErr(f.Index)
+		if err != nil || fv.IsZero() {
+			// Behind a nil pointer, or empty
+			continue
+		}
+		headers[f.Name] = fmt.Sprint(fv.Interface())
+	}
+	return headers
+}
@@ -0,0 +1,50 @@
+package hotelbyte
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+type channelOption struct {
+	Channel string `json:"channel,omitempty" api.header:"Channel"`
+}
+
+type headersReq struct {
+	*channelOption
+	protocol.CurrencyOption
+	protocol.SessionOption
+	protocol.TestOption
+	Header   protocol.CommonHeader `json:"header"`
+	Priority int                   `json:"-" api.header:"Priority"`
+	Guests   []protocol.Guest      `json:"guests"`
+}
+
+func TestRequestHeaders(t *testing.T) {
+	req := &headersReq{
+		CurrencyOption: protocol.CurrencyOption{Currency: "EUR"},
+		SessionOption:  protocol.SessionOption{SessionId: "s1"},
+		Header:         protocol.CommonHeader{RequestId: "req-1"},
+		Priority:       2,
+	}
+	want := map[string]string{"Currency": "EUR", "Session-Id": "s1", "Request-Id": "req-1", "Priority": "2"}
+	for i := 0; i < 2; i++ { // the second time from the cache
+		if got := requestHeaders(req); !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	}
+
+	req.channelOption = &channelOption{Channel: "web"}
+	req.TestOption = protocol.TestOption{Test: "scenario=slow"}
+	if got := requestHeaders(req); got["Channel"] != "web" || got["Test"] != "scenario=slow" {
+		t.Errorf("Expected the embedded pointer and test flags, got %v", got)
+	}
+
+	if got := requestHeaders(&protocol.QueryOrdersReq{}); len(got) != 0 {
+		t.Errorf("Expected no empty header, got %v", got)
+	}
+	if got := requestHeaders((*protocol.BookReq)(nil)); got != nil {
+		t.Errorf("Expected no header for a nil request, got %v", got)
+	}
+}
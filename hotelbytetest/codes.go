@@ -0,0 +1,16 @@
+package hotelbytetest
+
+// Business error codes of the fake server, on APIError.Code. The backend documents no codes yet, so
+// these are the fake's own: tests should match them only against this server. Server.NewClient maps
+// them to the sentinel errors of the SDK, e.g. CodePriceChanged to hotelbyte.ErrPriceChanged.
+const (
+	CodeSuccess            int32 = 0
+	CodeInvalidRequest     int32 = 1000 // the request is malformed or misses required fields
+	CodeUnauthorized       int32 = 1001 // invalid credentials, or a missing, invalid or expired ticket
+	CodeHotelNotFound      int32 = 2001
+	CodeRateUnavailable    int32 = 3001 // the rate package is sold out or unknown
+	CodePriceChanged       int32 = 3002 // the price of the rate package changed since it was quoted
+	CodeDuplicateReference int32 = 3003 // an order with the same CustomerReferenceNo exists
+	CodeOrderNotFound      int32 = 3004
+	CodeCancelFailed       int32 = 3005 // the supplier refused the cancellation
+)
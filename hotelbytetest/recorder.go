@@ -0,0 +1,384 @@
+// Package hotelbytetest provides utilities for testing code built on the HotelByte SDK without
+// reaching the real API
+package hotelbytetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	hotelbyte "github.com/hotelbyte-com/sdk-go"
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// Mode is the mode of a Recorder
+type Mode int
+
+const (
+	// ModeReplay serves the interactions of the cassette and fails on any other request; it never
+	// reaches the network
+	ModeReplay Mode = iota
+	// ModeRecord sends every request and saves the interactions to the cassette on Stop
+	ModeRecord
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrNoInteraction is returned in replay mode for requests the cassette has no interaction for
+var ErrNoInteraction = errors.New("hotelbytetest: no recorded interaction")
+
+// Cassette is the file of recorded interactions
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request interactions are matched on
+type RecordedRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   string            `json:"query,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"` // canonical JSON: sorted keys, no spaces, scrubbed
+}
+
+// RecordedResponse is a recorded response
+type RecordedResponse struct {
+	StatusCode int             `json:"statusCode"`
+	Headers    http.Header     `json:"headers,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`    // scrubbed JSON body
+	RawBody    string          `json:"rawBody,omitempty"` // non-JSON body, kept as is
+}
+
+// DefaultMatchHeaders returns the request headers interactions are matched on
+func DefaultMatchHeaders() []string {
+	return []string{"Test", "Session-Id", "Currency"}
+}
+
+// DefaultScrubHeaders returns the headers never written to cassettes
+func DefaultScrubHeaders() []string {
+	return []string{"Authorization", "Cookie", "Set-Cookie"}
+}
+
+// RecorderConfig represents record/replay configuration
+type RecorderConfig struct {
+	// Mode is ModeReplay or ModeRecord
+	Mode Mode
+	// Cassette is the path of the cassette file
+	Cassette string
+	// Transport sends the requests in record mode; defaults to http.DefaultTransport
+	Transport http.RoundTripper
+	// Redactor scrubs the PII fields of bodies, by the protocol types of the endpoint; secrets are
+	// always masked. Defaults to masking every PII field.
+	Redactor *hotelbyte.Redactor
+	// MatchHeaders are the request headers interactions are matched on; defaults to DefaultMatchHeaders
+	MatchHeaders []string
+	// ScrubHeaders are headers never written to cassettes, in addition to DefaultScrubHeaders
+	ScrubHeaders []string
+}
+
+// Recorder is an http.RoundTripper recording interactions to a cassette or replaying them from it.
+// Pass it to the client with hotelbyte.WithHTTPTransport.
+//
+// Requests are matched on method, path, query, MatchHeaders and the canonical body, after scrubbing,
+// so that replays match whatever the credentials and PII used. Identical requests are served
+// in recorded order; the last one is repeated once they run out.
+type Recorder struct {
+	config       RecorderConfig
+	scrubHeaders map[string]bool
+
+	mu       sync.Mutex
+	cassette *Cassette
+	served   map[*Interaction]bool
+}
+
+// NewRecorder creates a Recorder; in replay mode the cassette must exist
+func NewRecorder(config RecorderConfig) (*Recorder, error) {
+	if config.Cassette == "" {
+		return nil, fmt.Errorf("empty cassette path")
+	}
+	if config.Transport == nil {
+		config.Transport = http.DefaultTransport
+	}
+	if config.Redactor == nil {
+		config.Redactor = hotelbyte.NewRedactor(hotelbyte.RedactConfig{})
+	}
+	if config.MatchHeaders == nil {
+		config.MatchHeaders = DefaultMatchHeaders()
+	}
+	r := &Recorder{
+		config:       config,
+		scrubHeaders: make(map[string]bool),
+		cassette:     &Cassette{},
+		served:       make(map[*Interaction]bool),
+	}
+	for _, h := range append(DefaultScrubHeaders(), config.ScrubHeaders...) {
+		r.scrubHeaders[http.CanonicalHeaderKey(h)] = true
+	}
+
+	switch config.Mode {
+	case ModeReplay:
+		data, err := os.ReadFile(config.Cassette)
+		if err != nil {
+			return nil, fmt.Errorf("read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: %w", config.Cassette, err)
+		}
+		// Bodies are indented in the file, and may have been edited; match them in canonical form
+		for _, i := range r.cassette.Interactions {
+			if len(i.Request.Body) > 0 {
+				if i.Request.Body, err = canonical(i.Request.Body); err != nil {
+					return nil, fmt.Errorf("invalid cassette %s: %w", config.Cassette, err)
+				}
+			}
+		}
+	case ModeRecord:
+	default:
+		return nil, fmt.Errorf("unknown mode %v", config.Mode)
+	}
+	return r, nil
+}
+
+// Cassette returns the interactions recorded or loaded so far
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]*Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Stop saves the cassette in record mode; it does nothing in replay mode
+func (r *Recorder) Stop() error {
+	if r.config.Mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("marshal cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.config.Cassette), 0o755); err != nil {
+		return fmt.Errorf("create cassette dir: %w", err)
+	}
+	if err := os.WriteFile(r.config.Cassette, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded, err := r.recordRequest(req, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.config.Mode == ModeReplay {
+		i := r.match(recorded)
+		if i == nil {
+			return nil, fmt.Errorf("%w for %s %s", ErrNoInteraction, req.Method, req.URL.Path)
+		}
+		return i.Response.httpResponse(req), nil
+	}
+
+	resp, err := r.config.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	i := &Interaction{Request: *recorded, Response: r.recordResponse(req.URL.Path, resp, respBody)}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// match returns the first unserved interaction matching the request, or the last matching one
+func (r *Recorder) match(req *RecordedRequest) *Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var last *Interaction
+	for _, i := range r.cassette.Interactions {
+		if !i.Request.matches(req) {
+			continue
+		}
+		if !r.served[i] {
+			r.served[i] = true
+			return i
+		}
+		last = i
+	}
+	return last
+}
+
+func (r *RecordedRequest) matches(other *RecordedRequest) bool {
+	if r.Method != other.Method || r.Path != other.Path || r.Query != other.Query ||
+		!bytes.Equal(r.Body, other.Body) || len(r.Headers) != len(other.Headers) {
+		return false
+	}
+	for k, v := range r.Headers {
+		if other.Headers[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Recorder) recordRequest(req *http.Request, body []byte) (*RecordedRequest, error) {
+	recorded := &RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+	}
+	for _, h := range r.config.MatchHeaders {
+		if v := req.Header.Get(h); v != "" && !r.scrubHeaders[http.CanonicalHeaderKey(h)] {
+			if recorded.Headers == nil {
+				recorded.Headers = make(map[string]string)
+			}
+			recorded.Headers[http.CanonicalHeaderKey(h)] = v
+		}
+	}
+	if len(body) > 0 {
+		scrubbed, err := r.scrub(body, requestTypes[req.URL.Path])
+		if err != nil {
+			return nil, fmt.Errorf("request body of %s: %w", req.URL.Path, err)
+		}
+		recorded.Body = scrubbed
+	}
+	return recorded, nil
+}
+
+func (r *Recorder) recordResponse(path string, resp *http.Response, body []byte) RecordedResponse {
+	recorded := RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Headers:    make(http.Header),
+	}
+	for k, v := range resp.Header {
+		// Date changes on every recording, and Content-Length no longer holds once the body is scrubbed
+		if !r.scrubHeaders[k] && k != "Date" && k != "Content-Length" {
+			recorded.Headers[k] = append([]string(nil), v...)
+		}
+	}
+	if len(body) > 0 {
+		if scrubbed, err := r.scrub(body, responseTypes[path]); err == nil {
+			recorded.Body = scrubbed
+		} else {
+			recorded.RawBody = string(body)
+		}
+	}
+	return recorded
+}
+
+// scrub redacts a JSON body of the type of like and canonicalises it
+func (r *Recorder) scrub(body []byte, like any) (json.RawMessage, error) {
+	redacted, err := r.config.Redactor.RedactJSON(body, like)
+	if err != nil {
+		return nil, err
+	}
+	// Secrets are masked whatever the Redactor mode, so that cassettes can be committed
+	redacted, err = secretRedactor.RedactJSON(redacted, like)
+	if err != nil {
+		return nil, err
+	}
+	return canonical(redacted)
+}
+
+// canonical re-marshals JSON without spaces and with sorted keys, as encoding/json sorts map keys
+func canonical(raw []byte) (json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber() // keeps large IDs exact
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// secretRedactor masks secrets and keeps every other category
+var secretRedactor = hotelbyte.NewRedactor(hotelbyte.RedactConfig{
+	Mode:       hotelbyte.RedactKeep,
+	Categories: map[string]hotelbyte.RedactMode{hotelbyte.PIISecret: hotelbyte.RedactMask},
+	Keys:       []string{"appSecret", "ticket"},
+})
+
+func (r *RecordedResponse) httpResponse(req *http.Request) *http.Response {
+	body := []byte(r.Body)
+	if r.RawBody != "" {
+		body = []byte(r.RawBody)
+	}
+	header := r.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// requestTypes are the request body types of the endpoints, telling where their PII fields are
+var requestTypes = map[string]any{
+	protocol.PathAuthTicket:  (*protocol.AuthReq)(nil),
+	protocol.PathHotelList:   (*protocol.HotelListReq)(nil),
+	protocol.PathHotelRates:  (*protocol.HotelRatesReq)(nil),
+	protocol.PathCheckAvail:  (*protocol.CheckAvailReq)(nil),
+	protocol.PathBook:        (*protocol.BookReq)(nil),
+	protocol.PathQueryOrders: (*protocol.QueryOrdersReq)(nil),
+	protocol.PathCancel:      (*protocol.CancelReq)(nil),
+}
+
+// responseTypes are the response body types of the endpoints
+var responseTypes = map[string]any{
+	protocol.PathAuthTicket:  (*types.Response[protocol.AuthResp])(nil),
+	protocol.PathHotelList:   (*types.Response[protocol.HotelListResp])(nil),
+	protocol.PathHotelRates:  (*types.Response[protocol.HotelRatesResp])(nil),
+	protocol.PathCheckAvail:  (*types.Response[protocol.CheckAvailResp])(nil),
+	protocol.PathBook:        (*types.Response[protocol.BookResp])(nil),
+	protocol.PathQueryOrders: (*types.Response[protocol.QueryOrdersResp])(nil),
+	protocol.PathCancel:      (*types.Response[protocol.CancelResp])(nil),
+}
@@ -0,0 +1,221 @@
+package hotelbytetest
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	hotelbyte "github.com/hotelbyte-com/sdk-go"
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// record re-records testdata/booking_flow.json against HOTELBYTE_BASE_URL with HOTELBYTE_APP_KEY and
+// HOTELBYTE_APP_SECRET
+var record = flag.Bool("record", false, "record cassettes against the HotelByte test API")
+
+// bookingFlow runs every endpoint from HotelList to Cancel and returns the outcomes
+func bookingFlow(ctx context.Context, client *hotelbyte.Client) (string, error) {
+	list, err := client.HotelList(ctx, &protocol.HotelListReq{
+		HotelIds:   types.IDs{461850557},
+		CheckInOut: protocol.CheckInOut{CheckIn: 20260101, CheckOut: 20260103},
+		TestOption: protocol.TestOption{Test: "scenario=default"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("HotelList: %w", err)
+	}
+	rates, err := client.HotelRates(ctx, &protocol.HotelRatesReq{
+		HotelId:       list.List[0].ID,
+		CheckInOut:    protocol.CheckInOut{CheckIn: 20260101, CheckOut: 20260103},
+		SessionOption: protocol.SessionOption{SessionId: "session-1"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("HotelRates: %w", err)
+	}
+	ratePkgID := rates.Rooms[0].Rates[0].RatePkgId
+	avail, err := client.CheckAvail(ctx, &protocol.CheckAvailReq{
+		RatePkgId:     ratePkgID,
+		SessionOption: protocol.SessionOption{SessionId: "session-1"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("CheckAvail: %w", err)
+	}
+	book, err := client.Book(ctx, &protocol.BookReq{
+		RatePkgId:           ratePkgID,
+		CustomerReferenceNo: "ref-1",
+		Holder:              protocol.Holder{FirstName: "John", LastName: "Doe", Email: "john.doe@example.com"},
+		Guests:              []protocol.Guest{{RoomIndex: 1, FirstName: "John", LastName: "Doe", NationalityCode: "US"}},
+		SessionOption:       protocol.SessionOption{SessionId: "session-1"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("Book: %w", err)
+	}
+	orders, err := client.QueryOrders(ctx, &protocol.QueryOrdersReq{CustomerReferenceNos: []string{"ref-1"}})
+	if err != nil {
+		return "", fmt.Errorf("QueryOrders: %w", err)
+	}
+	cancel, err := client.Cancel(ctx, &protocol.CancelReq{
+		CustomerReferenceNo: "ref-1",
+		SupplierReferenceNo: orders.Orders[0].SupplierReferenceNo,
+	})
+	if err != nil {
+		return "", fmt.Errorf("Cancel: %w", err)
+	}
+	return fmt.Sprintf("hotel=%d rate=%s avail=%s book=%s orders=%d cancel=%s",
+		list.List[0].ID, ratePkgID, avail.Status, book.HotelOrder.Status, len(orders.Orders), cancel.Status), nil
+}
+
+// standIn serves canned responses to bookingFlow
+func standIn(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != protocol.PathAuthTicket && r.Header.Get("Authorization") != "Bearer live-ticket" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Trace-Id", "trace-"+strings.TrimPrefix(r.URL.Path, "/api/"))
+		switch r.URL.Path {
+		case protocol.PathAuthTicket:
+			fmt.Fprint(w, `{"code":0,"data":{"ticket":"live-ticket"}}`)
+		case protocol.PathHotelList:
+			fmt.Fprint(w, `{"code":0,"data":{"list":[{"id":461850557,"minPrice":{"amount":100,"currency":"USD"}}]}}`)
+		case protocol.PathHotelRates:
+			fmt.Fprint(w, `{"code":0,"data":{"rooms":[{"roomTypeId":"R001","rates":[{"ratePkgId":"pkg-1"}]}]}}`)
+		case protocol.PathCheckAvail:
+			fmt.Fprint(w, `{"code":0,"data":{"status":1}}`)
+		case protocol.PathBook:
+			fmt.Fprint(w, `{"code":0,"data":{"hotelOrder":{"status":2,"supplierReferenceNo":"sup-1",`+
+				`"holder":{"firstName":"John","lastName":"Doe","email":"john.doe@example.com"}}}}`)
+		case protocol.PathQueryOrders:
+			fmt.Fprint(w, `{"code":0,"data":{"orders":[{"status":2,"supplierReferenceNo":"sup-1"}]}}`)
+		case protocol.PathCancel:
+			fmt.Fprint(w, `{"code":0,"data":{"status":3}}`)
+		}
+	}))
+}
+
+func newClient(t *testing.T, baseURL, key, secret string, rt http.RoundTripper) *hotelbyte.Client {
+	t.Helper()
+	client, err := hotelbyte.NewClient(
+		hotelbyte.WithBaseURL(baseURL),
+		hotelbyte.WithCredentials(key, secret),
+		hotelbyte.WithHTTPTransport(rt),
+		hotelbyte.WithRetryConfig(0, time.Millisecond, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := standIn(t)
+	cassette := filepath.Join(t.TempDir(), "flow.json")
+
+	recorder, err := NewRecorder(RecorderConfig{Mode: ModeRecord, Cassette: cassette})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	recorded, err := bookingFlow(context.Background(), newClient(t, srv.URL, "key", "live-secret", recorder))
+	if err != nil {
+		t.Fatalf("recording failed: %v", err)
+	}
+	if err := recorder.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	for _, leak := range []string{"live-secret", "live-ticket", "Bearer", "John", "Doe", "john.doe"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("Expected %q to be scrubbed from the cassette", leak)
+		}
+	}
+
+	// Replay offline with other credentials
+	replayer, err := NewRecorder(RecorderConfig{Mode: ModeReplay, Cassette: cassette})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	client := newClient(t, "http://hotelbyte.invalid", "key", "other-secret", replayer)
+	replayed, err := bookingFlow(context.Background(), client)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if replayed != recorded {
+		t.Errorf("Expected replay %q, got %q", recorded, replayed)
+	}
+
+	_, err = client.Cancel(context.Background(), &protocol.CancelReq{CustomerReferenceNo: "unknown", SupplierReferenceNo: "unknown"})
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction, got %v", err)
+	}
+}
+
+func TestBookingFlowCassette(t *testing.T) {
+	cassette := filepath.Join("testdata", "booking_flow.json")
+	baseURL, key, secret := "http://hotelbyte.invalid", "key", "secret"
+	mode := ModeReplay
+	if *record {
+		mode = ModeRecord
+		baseURL, key, secret = os.Getenv("HOTELBYTE_BASE_URL"), os.Getenv("HOTELBYTE_APP_KEY"), os.Getenv("HOTELBYTE_APP_SECRET")
+		if baseURL == "" {
+			baseURL = "https://api-test.hotelbyte.com"
+		}
+	}
+
+	recorder, err := NewRecorder(RecorderConfig{Mode: mode, Cassette: cassette})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	defer func() {
+		if err := recorder.Stop(); err != nil {
+			t.Errorf("Stop failed: %v", err)
+		}
+	}()
+
+	got, err := bookingFlow(context.Background(), newClient(t, baseURL, key, secret, recorder))
+	if err != nil {
+		t.Fatalf("booking flow failed: %v", err)
+	}
+	if want := "hotel=461850557 rate=pkg-1 avail=available book=confirmed orders=1 cancel=cancelled"; !*record && got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// failingTransport fails the test on any network access
+type failingTransport struct{ t *testing.T }
+
+func (f failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.t.Errorf("unexpected request to %s", req.URL)
+	return nil, io.ErrUnexpectedEOF
+}
+
+func TestReplayNeverReachesNetwork(t *testing.T) {
+	replayer, err := NewRecorder(RecorderConfig{
+		Mode:      ModeReplay,
+		Cassette:  filepath.Join("testdata", "booking_flow.json"),
+		Transport: failingTransport{t},
+	})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	_, err = newClient(t, "http://hotelbyte.invalid", "key", "secret", replayer).
+		QueryOrders(context.Background(), &protocol.QueryOrdersReq{CustomerReferenceNos: []string{"other"}})
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction, got %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package hotelbytetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// Scenarios reproduced by this fake server only, selected by the "scenario" test flag as
+// protocol.TestScenarioPriceChange, the one documented for the sandbox, e.g.
+//
+//	protocol.TestFlags{Hotel: "HC1", Scenario: hotelbytetest.ScenarioSoldOut}
+const (
+	// ScenarioSoldOut answers CheckAvail with CheckAvailStatusUnavailable, and fails Book with CodeRateUnavailable
+	ScenarioSoldOut protocol.TestScenario = "soldOut"
+	// ScenarioStuckConfirming books orders that stay OrderStatus_Confirming
+	ScenarioStuckConfirming protocol.TestScenario = "stuckConfirming"
+	// ScenarioCancelFailed leaves cancelled orders in OrderStatus_CancelFailed
+	ScenarioCancelFailed protocol.TestScenario = "cancelFailed"
+	// ScenarioSlow delays responses by Delay
+	ScenarioSlow protocol.TestScenario = "slow"
+	// ScenarioRateLimited answers the first Count requests with 429 and Retry-After
+	ScenarioRateLimited protocol.TestScenario = "rateLimited"
+	// ScenarioServerError answers the first Count requests with Status
+	ScenarioServerError protocol.TestScenario = "serverError"
+)
+
+var scenarios = map[protocol.TestScenario]bool{
+	protocol.TestScenarioPriceChange: true,
+	ScenarioSoldOut:                  true,
+	ScenarioStuckConfirming:          true,
+	ScenarioCancelFailed:             true,
+	ScenarioSlow:                     true,
+	ScenarioRateLimited:              true,
+	ScenarioServerError:              true,
+}
+
+// Scenario is a scenario selected by test flags, with its parameters
+type Scenario struct {
+	Name       protocol.TestScenario
+	Hotel      string        // "hotel" flag: restricts booking scenarios to the hotel code, e.g. "HC1", or ID; empty means every hotel
+	Ratio      float64       // "ratio" flag of priceChange, fake server only; defaults to 1.1
+	Delay      time.Duration // "delay" flag of slow, e.g. "500ms"; defaults to 2s
+	Count      int           // "count" flag of rateLimited and serverError; defaults to 3
+	Status     int           // "status" flag of serverError; defaults to 503
+	RetryAfter int           // "retryAfter" flag of rateLimited, in seconds; defaults to 1
+}
+
+// ParseScenario parses test flags. It returns nil if they select no known scenario, as the sandbox
+// ignores flags it does not recognise, and an error if a known scenario has malformed parameters.
+func ParseScenario(test string) (*Scenario, error) {
+	flags, err := url.ParseQuery(test)
+	if err != nil {
+		return nil, fmt.Errorf("invalid test flags %q: %w", test, err)
+	}
+	name := protocol.TestScenario(flags.Get("scenario"))
+	if !scenarios[name] {
+		return nil, nil
+	}
+	sc := &Scenario{
+		Name:       name,
+		Hotel:      flags.Get("hotel"),
+		Ratio:      1.1,
+		Delay:      2 * time.Second,
+		Count:      3,
+		Status:     http.StatusServiceUnavailable,
+		RetryAfter: 1,
+	}
+	if v := flags.Get("ratio"); v != "" {
+		if sc.Ratio, err = strconv.ParseFloat(v, 64); err != nil || sc.Ratio <= 0 {
+			return nil, fmt.Errorf("invalid ratio %q", v)
+		}
+	}
+	if v := flags.Get("delay"); v != "" {
+		if sc.Delay, err = time.ParseDuration(v); err != nil || sc.Delay < 0 {
+			return nil, fmt.Errorf("invalid delay %q", v)
+		}
+	}
+	if v := flags.Get("count"); v != "" {
+		if sc.Count, err = strconv.Atoi(v); err != nil || sc.Count < 0 {
+			return nil, fmt.Errorf("invalid count %q", v)
+		}
+	}
+	if v := flags.Get("status"); v != "" {
+		if sc.Status, err = strconv.Atoi(v); err != nil || sc.Status < 500 || sc.Status > 599 {
+			return nil, fmt.Errorf("invalid status %q, must be 5xx", v)
+		}
+	}
+	if v := flags.Get("retryAfter"); v != "" {
+		if sc.RetryAfter, err = strconv.Atoi(v); err != nil || sc.RetryAfter < 0 {
+			return nil, fmt.Errorf("invalid retryAfter %q", v)
+		}
+	}
+	return sc, nil
+}
+
+// Is reports whether the scenario is name and applies to the hotel of the ID and code
+func (sc *Scenario) Is(name protocol.TestScenario, hotel types.ID, code string) bool {
+	return sc != nil && sc.Name == name &&
+		(sc.Hotel == "" || sc.Hotel == strconv.FormatInt(int64(hotel), 10) || code != "" && sc.Hotel == code)
+}
+
+// scaleRate multiplies every amount of a rate by ratio, rounded to cents
+func scaleRate(r protocol.Rate, ratio float64) protocol.Rate {
+	scale := func(m types.Money) types.Money {
+		m.Amount = math.Round(m.Amount*ratio*100) / 100
+		return m
+	}
+	r.CommissionableRate = scale(r.CommissionableRate)
+	r.NetRate = scale(r.NetRate)
+	r.GrossRate = scale(r.GrossRate)
+	return r
+}
+
+// ScenarioEngine reproduces scenarios deterministically. Its Middleware handles the transport
+// scenarios (slow, rateLimited, serverError) in front of any handler; the booking scenarios are
+// applied by the Server. Bursts are counted per endpoint and test flags.
+type ScenarioEngine struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// NewScenarioEngine creates a ScenarioEngine
+func NewScenarioEngine() *ScenarioEngine {
+	return &ScenarioEngine{calls: make(map[string]int)}
+}
+
+// Reset restarts every burst
+func (e *ScenarioEngine) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	clear(e.calls)
+}
+
+// Middleware applies the transport scenarios selected by the test flags of the request
+func (e *ScenarioEngine) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		test := testFlagsOf(r)
+		sc, err := ParseScenario(test)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			writeResponse[struct{}](w, http.StatusBadRequest, nil, types.NewBizErr(CodeInvalidRequest, err.Error()))
+			return
+		}
+		if sc == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch sc.Name {
+		case ScenarioSlow:
+			// The server notices a client going away only once the body is read
+			if r.Body != nil {
+				body, _ := io.ReadAll(r.Body)
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			select {
+			case <-time.After(sc.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		case ScenarioRateLimited:
+			if e.burst(r.URL.Path, test, sc.Count) {
+				w.Header().Set("Retry-After", strconv.Itoa(sc.RetryAfter))
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+		case ScenarioServerError:
+			if e.burst(r.URL.Path, test, sc.Count) {
+				http.Error(w, http.StatusText(sc.Status), sc.Status)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// burst counts a call and reports whether it is one of the first count
+func (e *ScenarioEngine) burst(path, test string, count int) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	key := path + "\x00" + test
+	e.calls[key]++
+	return e.calls[key] <= count
+}
+
+// testFlagsOf returns the test flags of a request: the Test header, or else the "test" field of the body
+func testFlagsOf(r *http.Request) string {
+	if v := r.Header.Get("Test"); v != "" {
+		return v
+	}
+	if r.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var opt protocol.TestOption
+	_ = json.Unmarshal(body, &opt)
+	return opt.Test
+}
@@ -0,0 +1,178 @@
+package hotelbytetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	hotelbyte "github.com/hotelbyte-com/sdk-go"
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+func TestParseScenario(t *testing.T) {
+	sc, err := ParseScenario("hotel=461850557&scenario=priceChange&ratio=1.5")
+	if err != nil || sc == nil {
+		t.Fatalf("ParseScenario failed: %v, %v", sc, err)
+	}
+	if sc.Ratio != 1.5 || sc.Count != 3 || !sc.Is(protocol.TestScenarioPriceChange, 461850557, "HC1") || sc.Is(protocol.TestScenarioPriceChange, 118062388, "HC2") {
+		t.Errorf("Unexpected scenario %+v", sc)
+	}
+	if sc, _ := ParseScenario("hotel=HC1&scenario=priceChange"); !sc.Is(protocol.TestScenarioPriceChange, 461850557, "HC1") || sc.Is(protocol.TestScenarioPriceChange, 118062388, "HC2") {
+		t.Errorf("Expected the scenario to apply to the hotel code HC1 only, got %+v", sc)
+	}
+	if sc, err := ParseScenario("scenario=unknown"); sc != nil || err != nil {
+		t.Errorf("Expected unknown scenarios to be ignored, got %v, %v", sc, err)
+	}
+	for _, test := range []string{"scenario=slow&delay=soon", "scenario=serverError&status=404", "scenario=rateLimited&count=-1"} {
+		if _, err := ParseScenario(test); err == nil {
+			t.Errorf("Expected %q to be rejected", test)
+		}
+	}
+}
+
+func TestScenarioPriceChange(t *testing.T) {
+	srv := NewServer(ServerConfig{})
+	defer srv.Close()
+	client := newServerClient(t, srv)
+	ctx := context.Background()
+	test := protocol.TestOption{Test: "hotel=HC1&scenario=priceChange&ratio=1.5"}
+
+	avail, err := client.CheckAvail(ctx, &protocol.CheckAvailReq{TestOption: test, SessionOption: protocol.SessionOption{SessionId: "session-1"}, RatePkgId: "461850557-R001-NR"})
+	if err != nil {
+		t.Fatalf("CheckAvail failed: %v", err)
+	}
+	if avail.Status != protocol.CheckAvailStatusAvailable || avail.RoomRatePkg.Rate.NetRate.Amount != 270 {
+		t.Errorf("Expected an available rate at 270, got %v at %v", avail.Status, avail.RoomRatePkg.Rate.NetRate)
+	}
+	_, err = client.Book(ctx, &protocol.BookReq{
+		TestOption:          test,
+		CustomerReferenceNo: "ref-1",
+		RatePkgId:           "461850557-R001-NR",
+		Holder:              protocol.Holder{FirstName: "John", LastName: "Doe"},
+		Guests:              []protocol.Guest{{RoomIndex: 1, FirstName: "John", LastName: "Doe"}},
+		SessionOption:       protocol.SessionOption{SessionId: "session-1"},
+	})
+	expectBizErr(t, err, CodePriceChanged)
+	if !errors.Is(err, hotelbyte.ErrPriceChanged) {
+		t.Errorf("Expected ErrPriceChanged, got %v", err)
+	}
+
+	// Other hotels are not affected
+	avail, err = client.CheckAvail(ctx, &protocol.CheckAvailReq{TestOption: test, SessionOption: protocol.SessionOption{SessionId: "session-1"}, RatePkgId: "118062388-R001-FLEX"})
+	if err != nil || avail.RoomRatePkg.Rate.NetRate.Amount != 120 {
+		t.Errorf("Expected the price of another hotel to be unchanged, got %v, %v", avail, err)
+	}
+}
+
+func TestScenarioSoldOut(t *testing.T) {
+	srv := NewServer(ServerConfig{})
+	defer srv.Close()
+	client := newServerClient(t, srv)
+	ctx := context.Background()
+	test := protocol.TestOption{Test: "scenario=soldOut"}
+
+	avail, err := client.CheckAvail(ctx, &protocol.CheckAvailReq{TestOption: test, SessionOption: protocol.SessionOption{SessionId: "session-1"}, RatePkgId: "461850557-R001-FLEX"})
+	if err != nil {
+		t.Fatalf("CheckAvail failed: %v", err)
+	}
+	if avail.Status != protocol.CheckAvailStatusUnavailable {
+		t.Errorf("Expected unavailable, got %v", avail.Status)
+	}
+	_, err = client.Book(ctx, &protocol.BookReq{
+		TestOption:          test,
+		CustomerReferenceNo: "ref-1",
+		RatePkgId:           "461850557-R001-FLEX",
+		Holder:              protocol.Holder{FirstName: "John", LastName: "Doe"},
+		Guests:              []protocol.Guest{{RoomIndex: 1, FirstName: "John", LastName: "Doe"}},
+		SessionOption:       protocol.SessionOption{SessionId: "session-1"},
+	})
+	expectBizErr(t, err, CodeRateUnavailable)
+}
+
+func TestScenarioStuckConfirmingAndCancelFailed(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv := NewServer(ServerConfig{Now: func() time.Time { return now }})
+	defer srv.Close()
+	client := newServerClient(t, srv)
+	ctx := context.Background()
+
+	book, err := client.Book(ctx, &protocol.BookReq{
+		TestOption:          protocol.TestOption{Test: "scenario=stuckConfirming"},
+		CustomerReferenceNo: "ref-1",
+		RatePkgId:           "118062388-R001-FLEX",
+		Holder:              protocol.Holder{FirstName: "John", LastName: "Doe"},
+		Guests:              []protocol.Guest{{RoomIndex: 1, FirstName: "John", LastName: "Doe"}},
+		SessionOption:       protocol.SessionOption{SessionId: "session-1"},
+	})
+	if err != nil {
+		t.Fatalf("Book failed: %v", err)
+	}
+	now = now.Add(time.Hour)
+	if o, _ := srv.Order("ref-1"); o.Status != protocol.OrderStatus_Confirming {
+		t.Errorf("Expected the order to stay confirming, got %v", o.Status)
+	}
+
+	cancelReq := &protocol.CancelReq{CustomerReferenceNo: "ref-1", SupplierReferenceNo: book.HotelOrder.SupplierReferenceNo}
+	cancelFailed := hotelbyte.WithTestFlags(protocol.TestFlags{Scenario: ScenarioCancelFailed})
+	cancel, err := client.Cancel(ctx, cancelReq, cancelFailed)
+	if err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if cancel.Status != protocol.OrderStatus_CancelFailed {
+		t.Errorf("Expected cancel failed, got %v", cancel.Status)
+	}
+	// The cancellation can be retried without the scenario
+	if cancel, err = client.Cancel(ctx, cancelReq); err != nil || cancel.Status != protocol.OrderStatus_Cancelled {
+		t.Errorf("Expected the retried cancellation to succeed, got %v, %v", cancel, err)
+	}
+}
+
+func TestScenarioBursts(t *testing.T) {
+	srv := NewServer(ServerConfig{})
+	defer srv.Close()
+	client := newServerClient(t, srv, hotelbyte.WithRetryConfig(3, time.Millisecond, time.Millisecond))
+	ctx := context.Background()
+	stay := protocol.CheckInOut{CheckIn: 20260101, CheckOut: 20260103}
+
+	for _, test := range []string{"scenario=rateLimited&count=2&retryAfter=0", "scenario=serverError&count=3&status=502"} {
+		_, err := client.HotelList(ctx, &protocol.HotelListReq{TestOption: protocol.TestOption{Test: test}, CheckInOut: stay})
+		if err != nil {
+			t.Errorf("Expected %q to recover within the retries, got %v", test, err)
+		}
+	}
+
+	// A burst longer than the retries fails, and restarts after Reset
+	test := protocol.TestOption{Test: "scenario=serverError&count=4"}
+	if _, err := client.HotelList(ctx, &protocol.HotelListReq{TestOption: test, CheckInOut: stay}); err == nil {
+		t.Error("Expected a burst of 4 errors to exhaust 3 retries")
+	}
+	if _, err := client.HotelList(ctx, &protocol.HotelListReq{TestOption: test, CheckInOut: stay}); err != nil {
+		t.Errorf("Expected the burst to be over, got %v", err)
+	}
+	srv.Scenarios().Reset()
+	if _, err := client.HotelList(ctx, &protocol.HotelListReq{TestOption: test, CheckInOut: stay}); err == nil {
+		t.Error("Expected the burst to restart after Reset")
+	}
+}
+
+func TestScenarioSlow(t *testing.T) {
+	srv := NewServer(ServerConfig{})
+	defer srv.Close()
+	client := newServerClient(t, srv)
+	stay := protocol.CheckInOut{CheckIn: 20260101, CheckOut: 20260103}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := client.HotelList(ctx, &protocol.HotelListReq{TestOption: protocol.TestOption{Test: "scenario=slow&delay=10s"}, CheckInOut: stay})
+	if err == nil || time.Since(started) > 5*time.Second {
+		t.Errorf("Expected the call to time out, got %v after %v", err, time.Since(started))
+	}
+
+	_, err = client.HotelList(context.Background(), &protocol.HotelListReq{TestOption: protocol.TestOption{Test: "scenario=slow&delay=bad"}, CheckInOut: stay})
+	if _, ok := types.CastBizErr(err); !ok {
+		t.Errorf("Expected malformed flags to be rejected with a BizError, got %v", err)
+	}
+}
@@ -0,0 +1,513 @@
+package hotelbytetest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+
+	hotelbyte "github.com/hotelbyte-com/sdk-go"
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// ServerConfig represents fake server configuration
+type ServerConfig struct {
+	// AppKey and AppSecret are the only credentials accepted; default to "test-key" and "test-secret"
+	AppKey    string
+	AppSecret string
+	// TicketTTL caps the lifetime of tickets, whatever the TTL requested; defaults to 24h
+	TicketTTL time.Duration
+	// ConfirmDelay is how long booked orders stay Confirming; with 0 they are confirmed on the first read after booking
+	ConfirmDelay time.Duration
+	// Hotels is the initial inventory; defaults to DefaultInventory()
+	Hotels []*protocol.Hotel
+	// HotelCodes are the hotel codes the "hotel" test flag accepts besides the hotel IDs, e.g. "HC1";
+	// default to HC1, HC2... in the order of Hotels
+	HotelCodes map[string]types.ID
+	// Now returns the current time; defaults to time.Now
+	Now func() time.Time
+}
+
+// Server is an in-process fake of the HotelByte API, serving the protocol types from a seedable
+// in-memory inventory. Tickets are validated, and orders move from Confirming to Confirmed to Cancelled.
+// The scenarios of the Test header are reproduced, see ParseScenario.
+type Server struct {
+	*httptest.Server
+	config ServerConfig
+
+	mu        sync.Mutex
+	tickets   map[string]time.Time // ticket -> expiry
+	hotels    []*protocol.Hotel
+	codes     map[types.ID]string   // hotel codes by ID
+	rates     map[string]*rateEntry // by RatePkgId
+	orders    []*order
+	sequence  int
+	scenarios *ScenarioEngine
+}
+
+// rateEntry is a rate package of the inventory
+type rateEntry struct {
+	hotel     *protocol.Hotel
+	room      *protocol.Room
+	rate      *protocol.RoomRatePkg
+	available bool
+	stay      protocol.CheckInOut // last quoted stay
+}
+
+type order struct {
+	*protocol.HotelOrder
+	bookedAt time.Time
+	stuck    bool // never confirmed, see ScenarioStuckConfirming
+}
+
+// NewServer starts a fake server; Close it when done
+func NewServer(config ServerConfig) *Server {
+	if config.AppKey == "" {
+		config.AppKey = "test-key"
+	}
+	if config.AppSecret == "" {
+		config.AppSecret = "test-secret"
+	}
+	if config.TicketTTL <= 0 {
+		config.TicketTTL = 24 * time.Hour
+	}
+	if config.Hotels == nil {
+		config.Hotels = DefaultInventory()
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	if config.HotelCodes == nil {
+		config.HotelCodes = make(map[string]types.ID, len(config.Hotels))
+		for i, h := range config.Hotels {
+			config.HotelCodes[fmt.Sprintf("HC%d", i+1)] = h.ID
+		}
+	}
+	s := &Server{
+		config:    config,
+		tickets:   make(map[string]time.Time),
+		codes:     make(map[types.ID]string, len(config.HotelCodes)),
+		rates:     make(map[string]*rateEntry),
+		scenarios: NewScenarioEngine(),
+	}
+	for code, id := range config.HotelCodes {
+		s.codes[id] = code
+	}
+	s.Seed(config.Hotels...)
+
+	mux := http.NewServeMux()
+	route(s, mux, protocol.PathAuthTicket, false, s.authTicket)
+	route(s, mux, protocol.PathHotelList, true, s.hotelList)
+	route(s, mux, protocol.PathHotelRates, true, s.hotelRates)
+	route(s, mux, protocol.PathCheckAvail, true, s.checkAvail)
+	route(s, mux, protocol.PathBook, true, s.book)
+	route(s, mux, protocol.PathQueryOrders, true, s.queryOrders)
+	route(s, mux, protocol.PathCancel, true, s.cancel)
+	s.Server = httptest.NewServer(s.scenarios.Middleware(mux))
+	return s
+}
+
+// Scenarios returns the scenario engine of the server, e.g. to Reset bursts between tests
+func (s *Server) Scenarios() *ScenarioEngine {
+	return s.scenarios
+}
+
+// NewClient returns a client of the server, with its credentials and the sentinel errors of its
+// codes, e.g. errors.Is(err, hotelbyte.ErrPriceChanged) on CodePriceChanged
+func (s *Server) NewClient(options ...hotelbyte.ClientOption) (*hotelbyte.Client, error) {
+	return hotelbyte.NewClient(append([]hotelbyte.ClientOption{
+		hotelbyte.WithBaseURL(s.URL),
+		hotelbyte.WithCredentials(s.config.AppKey, s.config.AppSecret),
+		hotelbyte.WithErrorCodes(hotelbyte.ErrNotFound, CodeHotelNotFound, CodeOrderNotFound),
+		hotelbyte.WithErrorCodes(hotelbyte.ErrRateUnavailable, CodeRateUnavailable),
+		hotelbyte.WithErrorCodes(hotelbyte.ErrPriceChanged, CodePriceChanged),
+		hotelbyte.WithErrorCodes(hotelbyte.ErrDuplicateReference, CodeDuplicateReference),
+	}, options...)...)
+}
+
+// Seed adds hotels to the inventory, replacing those with the same ID. Every rate package starts available.
+func (s *Server) Seed(hotels ...*protocol.Hotel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, h := range hotels {
+		s.hotels = slices.DeleteFunc(s.hotels, func(old *protocol.Hotel) bool { return old.ID == h.ID })
+		s.hotels = append(s.hotels, h)
+		for i := range h.Rooms {
+			room := &h.Rooms[i]
+			for j := range room.Rates {
+				rate := &room.Rates[j]
+				s.rates[rate.RatePkgId] = &rateEntry{hotel: h, room: room, rate: rate, available: true}
+			}
+		}
+	}
+}
+
+// SetAvailable marks a rate package available or sold out
+func (s *Server) SetAvailable(ratePkgID string, available bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.rates[ratePkgID]; ok {
+		e.available = available
+	}
+}
+
+// ExpireTickets invalidates every ticket issued so far
+func (s *Server) ExpireTickets() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.tickets)
+}
+
+// Order returns a copy of the order with the CustomerReferenceNo, as QueryOrders would
+func (s *Server) Order(customerReferenceNo string) (*protocol.HotelOrder, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, o := range s.orders {
+		if o.CustomerReferenceNo == customerReferenceNo {
+			return s.view(o), true
+		}
+	}
+	return nil, false
+}
+
+// route serves an endpoint, decoding Req and encoding the Response[Resp] envelope
+func route[Req, Resp any](s *Server, mux *http.ServeMux, path string, authorized bool,
+	handle func(w http.ResponseWriter, sc *Scenario, req *Req) (*Resp, *types.BizError)) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPost {
+			writeResponse[Resp](w, http.StatusMethodNotAllowed, nil,
+				types.NewBizErr(CodeInvalidRequest, "method not allowed"))
+			return
+		}
+		if authorized && !s.authorized(r) {
+			writeResponse[Resp](w, http.StatusUnauthorized, nil,
+				types.NewBizErr(CodeUnauthorized, "invalid or expired ticket"))
+			return
+		}
+		// Malformed flags were rejected by the scenario middleware
+		sc, _ := ParseScenario(testFlagsOf(r))
+		var req Req
+		body, err := io.ReadAll(r.Body)
+		if err == nil && len(body) > 0 {
+			err = sonic.Unmarshal(body, &req)
+		}
+		if err != nil {
+			writeResponse[Resp](w, http.StatusBadRequest, nil,
+				types.NewBizErr(CodeInvalidRequest, fmt.Sprintf("invalid body: %v", err)))
+			return
+		}
+
+		s.mu.Lock()
+		s.sequence++
+		w.Header().Set("Trace-Id", fmt.Sprintf("fake-trace-%d", s.sequence))
+		resp, bizErr := handle(w, sc, &req)
+		status := http.StatusOK
+		if bizErr != nil && bizErr.Code == CodeUnauthorized {
+			status = http.StatusUnauthorized
+		}
+		// Encode under the lock, as responses share the inventory
+		writeResponse(w, status, resp, bizErr)
+		s.mu.Unlock()
+	})
+}
+
+func writeResponse[T any](w http.ResponseWriter, status int, data *T, bizErr *types.BizError) {
+	envelope := types.Response[T]{BizError: types.BizError{Code: CodeSuccess, Msg: "success"}, Data: data}
+	if bizErr != nil {
+		envelope = types.Response[T]{BizError: *bizErr}
+	}
+	body, err := sonic.Marshal(&envelope)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	ticket, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiry, ok := s.tickets[ticket]
+	return ok && s.config.Now().Before(expiry)
+}
+
+func (s *Server) authTicket(_ http.ResponseWriter, _ *Scenario, req *protocol.AuthReq) (*protocol.AuthResp, *types.BizError) {
+	if req.AppKey != s.config.AppKey || req.AppSecret != s.config.AppSecret {
+		return nil, types.NewBizErr(CodeUnauthorized, "invalid credentials")
+	}
+	ttl := s.config.TicketTTL
+	if req.TTL > 0 && time.Duration(req.TTL)*time.Second < ttl {
+		ttl = time.Duration(req.TTL) * time.Second
+	}
+	ticket := fmt.Sprintf("fake-ticket-%d", s.sequence)
+	s.tickets[ticket] = s.config.Now().Add(ttl)
+	return &protocol.AuthResp{Ticket: ticket}, nil
+}
+
+func (s *Server) hotelList(_ http.ResponseWriter, _ *Scenario, req *protocol.HotelListReq) (*protocol.HotelListResp, *types.BizError) {
+	var hotels protocol.HotelList
+	for _, h := range s.hotels {
+		if len(req.HotelIds) > 0 && !slices.Contains(req.HotelIds, h.ID) {
+			continue
+		}
+		hotel := s.quote(h, req.CheckInOut, req.MaxRatesPerHotel)
+		hotels = append(hotels, hotel)
+	}
+
+	resp := &protocol.HotelListResp{PageResp: types.PageResp{Total: int64(len(hotels))}}
+	if req.PageSize > 0 {
+		offset := min(req.GetOffset(), int64(len(hotels)))
+		end := min(offset+req.PageSize, int64(len(hotels)))
+		resp.HasMore = end < int64(len(hotels))
+		hotels = hotels[offset:end]
+	}
+	resp.List = hotels
+	return resp, nil
+}
+
+func (s *Server) hotelRates(w http.ResponseWriter, _ *Scenario, req *protocol.HotelRatesReq) (*protocol.HotelRatesResp, *types.BizError) {
+	idx := slices.IndexFunc(s.hotels, func(h *protocol.Hotel) bool { return h.ID == req.HotelId })
+	if idx < 0 {
+		return nil, types.NewBizErr(CodeHotelNotFound, fmt.Sprintf("hotel %d not found", req.HotelId))
+	}
+	sessionID := req.SessionId
+	if sessionID == "" {
+		sessionID = fmt.Sprintf("fake-session-%d", s.sequence)
+	}
+	w.Header().Set("Session-Id", sessionID)
+
+	hotel := s.quote(s.hotels[idx], req.CheckInOut, 0)
+	resp := &protocol.HotelRatesResp{}
+	for i := range hotel.Rooms {
+		resp.Rooms = append(resp.Rooms, &hotel.Rooms[i])
+	}
+	return resp, nil
+}
+
+// quote returns a copy of the hotel with the available rates for the stay, at most maxRates per hotel if > 0
+func (s *Server) quote(h *protocol.Hotel, stay protocol.CheckInOut, maxRates int64) *protocol.Hotel {
+	if stay.CheckIn == 0 || stay.CheckOut <= stay.CheckIn {
+		stay = defaultStay(s.config.Now())
+	}
+	hotel := *h
+	hotel.Rooms = nil
+	hotel.IsAvailable = false
+	hotel.MinPrice = types.Money{}
+	count := int64(0)
+	for _, room := range h.Rooms {
+		quoted := room
+		quoted.Rates = nil
+		for _, rate := range room.Rates {
+			e := s.rates[rate.RatePkgId]
+			if !e.available || (maxRates > 0 && count >= maxRates) {
+				continue
+			}
+			e.stay = stay
+			rate.CheckInOut = stay
+			quoted.Rates = append(quoted.Rates, rate)
+			count++
+			if !hotel.IsAvailable || rate.TotalRate.NetRate.Amount < hotel.MinPrice.Amount {
+				hotel.MinPrice = rate.TotalRate.NetRate
+			}
+			hotel.IsAvailable = true
+		}
+		if len(quoted.Rates) > 0 {
+			hotel.Rooms = append(hotel.Rooms, quoted)
+		}
+	}
+	return &hotel
+}
+
+func (s *Server) checkAvail(_ http.ResponseWriter, sc *Scenario, req *protocol.CheckAvailReq) (*protocol.CheckAvailResp, *types.BizError) {
+	e, ok := s.rates[req.RatePkgId]
+	if !ok {
+		return nil, types.NewBizErr(CodeRateUnavailable, fmt.Sprintf("rate package %q not found", req.RatePkgId))
+	}
+	if !e.available || sc.Is(ScenarioSoldOut, e.hotel.ID, s.codes[e.hotel.ID]) {
+		return &protocol.CheckAvailResp{Status: protocol.CheckAvailStatusUnavailable}, nil
+	}
+	rate := *e.rate
+	rate.CheckInOut = e.stayOr(s.config.Now())
+	if sc.Is(protocol.TestScenarioPriceChange, e.hotel.ID, s.codes[e.hotel.ID]) {
+		rate.Rate = scaleRate(rate.Rate, sc.Ratio)
+		rate.TotalRate = scaleRate(rate.TotalRate, sc.Ratio)
+	}
+	return &protocol.CheckAvailResp{Status: protocol.CheckAvailStatusAvailable, RoomRatePkg: &rate}, nil
+}
+
+func (e *rateEntry) stayOr(now time.Time) protocol.CheckInOut {
+	if e.stay.CheckIn == 0 {
+		return defaultStay(now)
+	}
+	return e.stay
+}
+
+func (s *Server) book(_ http.ResponseWriter, sc *Scenario, req *protocol.BookReq) (*protocol.BookResp, *types.BizError) {
+	if req.CustomerReferenceNo == "" || req.Holder.FirstName == "" || req.Holder.LastName == "" {
+		return nil, types.NewBizErr(CodeInvalidRequest, "customerReferenceNo and holder name are required")
+	}
+	for _, o := range s.orders {
+		if o.CustomerReferenceNo == req.CustomerReferenceNo {
+			return nil, types.NewBizErr(CodeDuplicateReference,
+				fmt.Sprintf("customerReferenceNo %q already booked", req.CustomerReferenceNo))
+		}
+	}
+	e, ok := s.rates[req.RatePkgId]
+	if !ok || !e.available || sc.Is(ScenarioSoldOut, e.hotel.ID, s.codes[e.hotel.ID]) {
+		return nil, types.NewBizErr(CodeRateUnavailable, fmt.Sprintf("rate package %q unavailable", req.RatePkgId))
+	}
+	if sc.Is(protocol.TestScenarioPriceChange, e.hotel.ID, s.codes[e.hotel.ID]) {
+		return nil, types.NewBizErr(CodePriceChanged, fmt.Sprintf("price of rate package %q changed", req.RatePkgId))
+	}
+
+	now := s.config.Now()
+	stay := e.stayOr(now)
+	rate := *e.rate
+	rate.CheckInOut = stay
+	o := &order{
+		HotelOrder: &protocol.HotelOrder{
+			OrderBasic: &protocol.OrderBasic{
+				Status:              protocol.OrderStatus_Confirming,
+				CheckIn:             stay.CheckIn,
+				CheckOut:            stay.CheckOut,
+				NightCount:          int64(stay.CheckOut.Sub(stay.CheckIn)),
+				RoomCount:           1,
+				BookingTime:         now,
+				Holder:              req.Holder,
+				CustomerReferenceNo: req.CustomerReferenceNo,
+				SupplierReferenceNo: fmt.Sprintf("FAKE-%06d", len(s.orders)+1),
+				Rate:                rate.TotalRate,
+			},
+			Hotel: &protocol.OrderHotelInfo{HotelId: e.hotel.ID, HotelStaticProfile: e.hotel.HotelStaticProfile},
+			Rooms: []*protocol.OrderRoomInfo{{
+				Room:        protocol.Room{RoomTypeId: e.room.RoomTypeId, RoomTypeName: e.room.RoomTypeName, HotelId: e.hotel.ID},
+				RoomRatePkg: rate,
+				RoomIndex:   1,
+				Guests:      req.Guests,
+			}},
+		},
+		bookedAt: now,
+		stuck:    sc.Is(ScenarioStuckConfirming, e.hotel.ID, s.codes[e.hotel.ID]),
+	}
+	s.orders = append(s.orders, o)
+	return &protocol.BookResp{HotelOrder: s.view(o)}, nil
+}
+
+func (s *Server) queryOrders(_ http.ResponseWriter, _ *Scenario, req *protocol.QueryOrdersReq) (*protocol.QueryOrdersResp, *types.BizError) {
+	resp := &protocol.QueryOrdersResp{Orders: []*protocol.HotelOrder{}}
+	for _, o := range s.orders {
+		s.advance(o)
+		if len(req.CustomerReferenceNos) > 0 && !slices.Contains(req.CustomerReferenceNos, o.CustomerReferenceNo) ||
+			len(req.SupplierReferenceNos) > 0 && !slices.Contains(req.SupplierReferenceNos, o.SupplierReferenceNo) ||
+			len(req.StatusList) > 0 && !slices.Contains(req.StatusList, o.Status) {
+			continue
+		}
+		resp.Orders = append(resp.Orders, s.view(o))
+	}
+	return resp, nil
+}
+
+func (s *Server) cancel(_ http.ResponseWriter, sc *Scenario, req *protocol.CancelReq) (*protocol.CancelResp, *types.BizError) {
+	idx := slices.IndexFunc(s.orders, func(o *order) bool {
+		return o.CustomerReferenceNo == req.CustomerReferenceNo && o.SupplierReferenceNo == req.SupplierReferenceNo
+	})
+	if idx < 0 {
+		return nil, types.NewBizErr(CodeOrderNotFound, fmt.Sprintf("order %q not found", req.CustomerReferenceNo))
+	}
+	o := s.orders[idx]
+	s.advance(o)
+	switch o.Status {
+	case protocol.OrderStatus_Confirming, protocol.OrderStatus_Confirmed, protocol.OrderStatus_CancelFailed:
+		if sc.Is(ScenarioCancelFailed, o.Hotel.HotelId, s.codes[o.Hotel.HotelId]) {
+			o.Status = protocol.OrderStatus_CancelFailed
+			o.StatusRemark = "supplier refused the cancellation"
+			break
+		}
+		o.Status = protocol.OrderStatus_Cancelled
+		o.CancelTime = s.config.Now()
+		o.RefundedPrice = o.Rate.NetRate
+	case protocol.OrderStatus_Cancelled:
+		// Cancelling again is a no-op
+	default:
+		return nil, types.NewBizErr(CodeCancelFailed, fmt.Sprintf("order in status %s cannot be cancelled", o.Status))
+	}
+	return &protocol.CancelResp{Status: o.Status, ServiceFee: types.Money{Currency: o.Rate.NetRate.Currency}}, nil
+}
+
+// advance confirms the order once ConfirmDelay has passed, unless it is stuck
+func (s *Server) advance(o *order) {
+	if o.Status == protocol.OrderStatus_Confirming && !o.stuck && !s.config.Now().Before(o.bookedAt.Add(s.config.ConfirmDelay)) {
+		o.Status = protocol.OrderStatus_Confirmed
+		o.HotelConfirmNo = "HC-" + o.SupplierReferenceNo
+	}
+}
+
+// view returns a copy of the order that later state changes do not affect
+func (s *Server) view(o *order) *protocol.HotelOrder {
+	basic := *o.OrderBasic
+	return &protocol.HotelOrder{OrderBasic: &basic, Hotel: o.Hotel, Rooms: o.Rooms}
+}
+
+// defaultStay is one night from tomorrow
+func defaultStay(now time.Time) protocol.CheckInOut {
+	checkIn := types.NewDateIntFromTime(now).AddDays(1)
+	return protocol.CheckInOut{CheckIn: checkIn, CheckOut: checkIn.AddDays(1)}
+}
+
+// DefaultInventory returns two hotels with a refundable and a non-refundable rate per room
+func DefaultInventory() []*protocol.Hotel {
+	rate := func(id string, amount float64, mode protocol.RefundableMode, board protocol.BoardId) protocol.RoomRatePkg {
+		price := types.Money{Currency: "USD", Amount: amount}
+		return protocol.RoomRatePkg{
+			RatePkgId:            id,
+			ComputedCancelPolicy: protocol.ComputedCancelPolicy{RefundableMode: mode},
+			Rate:                 protocol.Rate{NetRate: price},
+			TotalRate:            protocol.Rate{NetRate: price},
+			RatePlan:             protocol.RatePlan{Board: protocol.Board{BoardId: board}},
+		}
+	}
+	return []*protocol.Hotel{
+		{
+			ID: 461850557,
+			HotelStaticProfile: protocol.HotelStaticProfile{
+				Name: types.I18N{En: "Jumeirah Beach Hotel"},
+				Star: 5,
+			},
+			Rooms: []protocol.Room{
+				{RoomTypeId: "R001", RoomTypeName: types.I18N{En: "Standard Room"}, HotelId: 461850557, Rates: []protocol.RoomRatePkg{
+					rate("461850557-R001-FLEX", 220, protocol.RefundableModeFully, protocol.BoardIdBedBreakfast),
+					rate("461850557-R001-NR", 180, protocol.RefundableModeNo, protocol.BoardIdRoomOnly),
+				}},
+				{RoomTypeId: "R002", RoomTypeName: types.I18N{En: "Deluxe Sea View"}, HotelId: 461850557, Rates: []protocol.RoomRatePkg{
+					rate("461850557-R002-FLEX", 340, protocol.RefundableModeFully, protocol.BoardIdBedBreakfast),
+				}},
+			},
+		},
+		{
+			ID: 118062388,
+			HotelStaticProfile: protocol.HotelStaticProfile{
+				Name: types.I18N{En: "Millennium Al Barsha"},
+				Star: 4,
+			},
+			Rooms: []protocol.Room{
+				{RoomTypeId: "R001", RoomTypeName: types.I18N{En: "Standard Room"}, HotelId: 118062388, Rates: []protocol.RoomRatePkg{
+					rate("118062388-R001-FLEX", 120, protocol.RefundableModeFully, protocol.BoardIdRoomOnly),
+					rate("118062388-R001-PART", 105, protocol.RefundableModePartially, protocol.BoardIdRoomOnly),
+				}},
+			},
+		},
+	}
+}
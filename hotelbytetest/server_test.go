@@ -0,0 +1,180 @@
+package hotelbytetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	hotelbyte "github.com/hotelbyte-com/sdk-go"
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+func newServerClient(t *testing.T, srv *Server, options ...hotelbyte.ClientOption) *hotelbyte.Client {
+	t.Helper()
+	client, err := srv.NewClient(append([]hotelbyte.ClientOption{
+		hotelbyte.WithRetryConfig(0, time.Millisecond, time.Millisecond),
+	}, options...)...)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func expectBizErr(t *testing.T, err error, code int32) {
+	t.Helper()
+	bizErr, ok := types.CastBizErr(err)
+	if !ok || bizErr.Code != code {
+		t.Errorf("Expected BizError %d, got %v", code, err)
+	}
+}
+
+func TestServerBookingFlow(t *testing.T) {
+	srv := NewServer(ServerConfig{})
+	defer srv.Close()
+	client := newServerClient(t, srv)
+	ctx := context.Background()
+	stay := protocol.CheckInOut{CheckIn: 20260101, CheckOut: 20260103}
+
+	list, err := client.HotelList(ctx, &protocol.HotelListReq{CheckInOut: stay, PageReq: types.PageReq{PageNum: 1, PageSize: 1}})
+	if err != nil {
+		t.Fatalf("HotelList failed: %v", err)
+	}
+	if len(list.List) != 1 || !list.HasMore || list.Total != 2 {
+		t.Fatalf("Unexpected hotel list page: %d hotels, total %d, hasMore %v", len(list.List), list.Total, list.HasMore)
+	}
+	if list.List[0].MinPrice.Amount != 180 {
+		t.Errorf("Expected min price 180, got %v", list.List[0].MinPrice)
+	}
+
+	rates, err := client.HotelRates(ctx, &protocol.HotelRatesReq{HotelId: list.List[0].ID, CheckInOut: stay, SessionOption: protocol.SessionOption{SessionId: "session-1"}})
+	if err != nil {
+		t.Fatalf("HotelRates failed: %v", err)
+	}
+	rate := rates.Rooms[0].Rates[0]
+
+	avail, err := client.CheckAvail(ctx, &protocol.CheckAvailReq{RatePkgId: rate.RatePkgId, SessionOption: protocol.SessionOption{SessionId: "session-1"}})
+	if err != nil {
+		t.Fatalf("CheckAvail failed: %v", err)
+	}
+	if avail.Status != protocol.CheckAvailStatusAvailable || avail.RoomRatePkg.CheckIn != stay.CheckIn {
+		t.Errorf("Unexpected availability %v of %v", avail.Status, avail.RoomRatePkg)
+	}
+
+	bookReq := &protocol.BookReq{
+		CustomerReferenceNo: "ref-1",
+		RatePkgId:           rate.RatePkgId,
+		Holder:              protocol.Holder{FirstName: "John", LastName: "Doe"},
+		Guests:              []protocol.Guest{{RoomIndex: 1, FirstName: "John", LastName: "Doe"}},
+		SessionOption:       protocol.SessionOption{SessionId: "session-1"},
+	}
+	book, err := client.Book(ctx, bookReq)
+	if err != nil {
+		t.Fatalf("Book failed: %v", err)
+	}
+	if book.HotelOrder.Status != protocol.OrderStatus_Confirming || book.HotelOrder.NightCount != 2 {
+		t.Errorf("Unexpected order %+v", book.HotelOrder.OrderBasic)
+	}
+	_, err = client.Book(ctx, bookReq)
+	expectBizErr(t, err, CodeDuplicateReference)
+
+	orders, err := client.QueryOrders(ctx, &protocol.QueryOrdersReq{CustomerReferenceNos: []string{"ref-1"}})
+	if err != nil {
+		t.Fatalf("QueryOrders failed: %v", err)
+	}
+	if len(orders.Orders) != 1 || orders.Orders[0].Status != protocol.OrderStatus_Confirmed {
+		t.Fatalf("Expected one confirmed order, got %+v", orders.Orders)
+	}
+
+	cancel, err := client.Cancel(ctx, &protocol.CancelReq{
+		CustomerReferenceNo: "ref-1",
+		SupplierReferenceNo: orders.Orders[0].SupplierReferenceNo,
+	})
+	if err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if cancel.Status != protocol.OrderStatus_Cancelled {
+		t.Errorf("Expected cancelled, got %v", cancel.Status)
+	}
+	if o, _ := srv.Order("ref-1"); o.Status != protocol.OrderStatus_Cancelled {
+		t.Errorf("Expected the server to hold a cancelled order, got %v", o.Status)
+	}
+
+	_, err = client.Cancel(ctx, &protocol.CancelReq{CustomerReferenceNo: "unknown", SupplierReferenceNo: "unknown"})
+	expectBizErr(t, err, CodeOrderNotFound)
+	if !hotelbyte.IsNotFound(err) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+}
+
+func TestServerConfirmDelay(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv := NewServer(ServerConfig{ConfirmDelay: time.Minute, Now: func() time.Time { return now }})
+	defer srv.Close()
+	client := newServerClient(t, srv)
+	ctx := context.Background()
+
+	_, err := client.Book(ctx, &protocol.BookReq{
+		CustomerReferenceNo: "ref-1",
+		RatePkgId:           "118062388-R001-FLEX",
+		Holder:              protocol.Holder{FirstName: "John", LastName: "Doe"},
+		Guests:              []protocol.Guest{{RoomIndex: 1, FirstName: "John", LastName: "Doe"}},
+		SessionOption:       protocol.SessionOption{SessionId: "session-1"},
+	})
+	if err != nil {
+		t.Fatalf("Book failed: %v", err)
+	}
+	query := &protocol.QueryOrdersReq{CustomerReferenceNos: []string{"ref-1"}}
+	if orders, _ := client.QueryOrders(ctx, query); orders.Orders[0].Status != protocol.OrderStatus_Confirming {
+		t.Errorf("Expected confirming before the delay, got %v", orders.Orders[0].Status)
+	}
+	now = now.Add(time.Minute)
+	if orders, _ := client.QueryOrders(ctx, query); orders.Orders[0].Status != protocol.OrderStatus_Confirmed {
+		t.Errorf("Expected confirmed after the delay, got %v", orders.Orders[0].Status)
+	}
+}
+
+func TestServerSoldOut(t *testing.T) {
+	srv := NewServer(ServerConfig{})
+	defer srv.Close()
+	client := newServerClient(t, srv)
+	ctx := context.Background()
+
+	srv.SetAvailable("461850557-R002-FLEX", false)
+	avail, err := client.CheckAvail(ctx, &protocol.CheckAvailReq{RatePkgId: "461850557-R002-FLEX", SessionOption: protocol.SessionOption{SessionId: "session-1"}})
+	if err != nil {
+		t.Fatalf("CheckAvail failed: %v", err)
+	}
+	if avail.Status != protocol.CheckAvailStatusUnavailable {
+		t.Errorf("Expected unavailable, got %v", avail.Status)
+	}
+	_, err = client.Book(ctx, &protocol.BookReq{
+		CustomerReferenceNo: "ref-1",
+		RatePkgId:           "461850557-R002-FLEX",
+		Holder:              protocol.Holder{FirstName: "John", LastName: "Doe"},
+		Guests:              []protocol.Guest{{RoomIndex: 1, FirstName: "John", LastName: "Doe"}},
+		SessionOption:       protocol.SessionOption{SessionId: "session-1"},
+	})
+	expectBizErr(t, err, CodeRateUnavailable)
+}
+
+func TestServerTickets(t *testing.T) {
+	srv := NewServer(ServerConfig{})
+	defer srv.Close()
+	ctx := context.Background()
+
+	client := newServerClient(t, srv)
+	if _, err := client.QueryOrders(ctx, &protocol.QueryOrdersReq{}); err != nil {
+		t.Fatalf("QueryOrders failed: %v", err)
+	}
+	// An expired ticket is re-acquired and the call replayed
+	srv.ExpireTickets()
+	if _, err := client.QueryOrders(ctx, &protocol.QueryOrdersReq{}); err != nil {
+		t.Fatalf("QueryOrders after ticket expiry failed: %v", err)
+	}
+
+	intruder := newServerClient(t, srv, hotelbyte.WithCredentials("test-key", "wrong"))
+	_, err := intruder.QueryOrders(ctx, &protocol.QueryOrdersReq{})
+	expectBizErr(t, err, CodeUnauthorized)
+}
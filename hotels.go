@@ -2,170 +2,142 @@ package hotelbyte
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 
+	"github.com/bytedance/sonic"
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/hotelbyte-com/sdk-go/protocol"
 	"github.com/hotelbyte-com/sdk-go/protocol/types"
 )
 
-func (s *Client) HotelList(ctx context.Context, req *protocol.HotelListReq) (*protocol.HotelListResp, error) {
-	// Ensure user is authenticated
-	if err := s.Authenticate(ctx); err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
-	}
-
-	// Build request based on real backend structure
-	httpReq := &types.HttpRequest{
-		Method: http.MethodPost,
-		Path:   "/api/search/hotelList",
-		Headers: map[string]string{
-			"Authorization": s.GetAuthorizationHeader(),
-			"Test":          req.Test,     // Pass test flags if any
-			"Currency":      req.Currency, // Pass currency if any
+func (s *Client) HotelList(ctx context.Context, req *protocol.HotelListReq, opts ...CallOption) (*protocol.HotelListResp, error) {
+	return call[protocol.HotelListReq, protocol.HotelListResp](ctx, s, endpoint{
+		name:   "HotelList",
+		method: http.MethodPost,
+		path:   protocol.PathHotelList,
+		attrs: func() []attribute.KeyValue {
+			return []attribute.KeyValue{AttrHotelIDCount.Int(len(req.HotelIds))}
 		},
-		Body: req, // Use the entire request structure
-	}
-
-	// Send request
-	resp, err := s.transport.Do(ctx, httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("hotel search request failed: %w", err)
-	}
-
-	return types.NewResponseData[protocol.HotelListResp](resp)
+	}, req, opts)
 }
 
-func (s *Client) HotelRates(ctx context.Context, req *protocol.HotelRatesReq) (*protocol.HotelRatesResp, error) {
-	// Ensure user is authenticated
-	if err := s.Authenticate(ctx); err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
-	}
-
-	// Build request
-	httpReq := &types.HttpRequest{
-		Method: http.MethodPost,
-		Path:   "/api/search/hotelRates",
-		Headers: map[string]string{
-			"Authorization": s.GetAuthorizationHeader(),
-			"Session-Id":    req.SessionId,
-			"Test":          req.Test,     // Pass test flags if any
-			"Currency":      req.Currency, // Pass currency if any
+func (s *Client) HotelRates(ctx context.Context, req *protocol.HotelRatesReq, opts ...CallOption) (*protocol.HotelRatesResp, error) {
+	return call[protocol.HotelRatesReq, protocol.HotelRatesResp](ctx, s, endpoint{
+		name:   "HotelRates",
+		method: http.MethodPost,
+		path:   protocol.PathHotelRates,
+		attrs: func() []attribute.KeyValue {
+			hotels := 0
+			if req.HotelId != 0 {
+				hotels = 1
+			}
+			return []attribute.KeyValue{AttrHotelIDCount.Int(hotels), AttrSessionID.String(req.SessionId)}
 		},
-		Body: req, // Use the entire request structure
-	}
-
-	// Send request
-	resp, err := s.transport.Do(ctx, httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("get hotel rates request failed: %w", err)
-	}
-
-	return types.NewResponseData[protocol.HotelRatesResp](resp)
+	}, req, opts)
 }
 
-func (s *Client) CheckAvail(ctx context.Context, req *protocol.CheckAvailReq) (*protocol.CheckAvailResp, error) {
-	// Ensure user is authenticated
-	if err := s.Authenticate(ctx); err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
-	}
-
-	// Build request
-	httpReq := &types.HttpRequest{
-		Method: http.MethodPost,
-		Path:   "/api/search/checkAvail",
-		Headers: map[string]string{
-			"Authorization": s.GetAuthorizationHeader(),
-			"Session-Id":    req.SessionId,
-			"Test":          req.Test, // Pass test flags if any
+func (s *Client) CheckAvail(ctx context.Context, req *protocol.CheckAvailReq, opts ...CallOption) (*protocol.CheckAvailResp, error) {
+	r, err := call[protocol.CheckAvailReq, protocol.CheckAvailResp](ctx, s, endpoint{
+		name:   "CheckAvail",
+		method: http.MethodPost,
+		path:   protocol.PathCheckAvail,
+		attrs: func() []attribute.KeyValue {
+			return []attribute.KeyValue{AttrSessionID.String(req.SessionId)}
 		},
-		Body: req, // Use the entire request structure
-	}
-
-	// Send request
-	resp, err := s.transport.Do(ctx, httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("get hotel rates request failed: %w", err)
+	}, req, opts)
+	if err == nil {
+		s.recordCheckAvailStatus(r.Status)
 	}
-
-	return types.NewResponseData[protocol.CheckAvailResp](resp)
+	return r, err
 }
 
-func (s *Client) Book(ctx context.Context, req *protocol.BookReq) (*protocol.BookResp, error) {
-	// Ensure user is authenticated
-	if err := s.Authenticate(ctx); err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
-	}
-
-	// Build request
-	httpReq := &types.HttpRequest{
-		Method: http.MethodPost,
-		Path:   "/api/trade/book",
-		Headers: map[string]string{
-			"Authorization": s.GetAuthorizationHeader(),
-			"Session-Id":    req.SessionId,
-			"Test":          req.Test, // Pass test flags if any
+func (s *Client) Book(ctx context.Context, req *protocol.BookReq, opts ...CallOption) (*protocol.BookResp, error) {
+	r, err := call[protocol.BookReq, protocol.BookResp](ctx, s, endpoint{
+		name:   "Book",
+		method: http.MethodPost,
+		path:   protocol.PathBook,
+		attrs: func() []attribute.KeyValue {
+			return []attribute.KeyValue{
+				AttrSessionID.String(req.SessionId),
+				AttrCustomerReferenceNo.String(req.CustomerReferenceNo),
+			}
 		},
-		Body: req, // Use the entire request structure
-	}
-
-	// Send request
-	resp, err := s.transport.Do(ctx, httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("get hotel rates request failed: %w", err)
+	}, req, opts)
+	if err == nil && r.HotelOrder != nil && r.HotelOrder.OrderBasic != nil {
+		s.recordOrderStatus(protocol.PathBook, r.HotelOrder.Status)
 	}
-
-	return types.NewResponseData[protocol.BookResp](resp)
+	return r, err
 }
 
-func (s *Client) QueryOrders(ctx context.Context, req *protocol.QueryOrdersReq) (*protocol.QueryOrdersResp, error) {
-	// Ensure user is authenticated
-	if err := s.Authenticate(ctx); err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
+// reconcileBook looks the order up by CustomerReferenceNo after a booking attempt failed with an
+// unknown outcome, so that an order created by the lost attempt is returned instead of booked twice.
+// The lookup is a child span of the Book call, which is metered by itself, and the call options of
+// Book, e.g. its RetryPolicy, do not apply to it.
+func (s *Client) reconcileBook(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, bool) {
+	bookReq, ok := req.Body.(*protocol.BookReq)
+	if !ok || bookReq.CustomerReferenceNo == "" {
+		return nil, false
+	}
+	ctx, span := s.startSpan(detachCall(ctx), "reconcileBook", protocol.PathQueryOrders,
+		AttrCustomerReferenceNo.String(bookReq.CustomerReferenceNo))
+	var err error
+	defer func() { endSpan(span, err) }()
+
+	queryReq := &protocol.QueryOrdersReq{
+		CustomerReferenceNos: []string{bookReq.CustomerReferenceNo},
+		TestOption:           bookReq.TestOption,
+	}
+	resp, err := s.doAuthorized(ctx, &types.HttpRequest{
+		Method:  http.MethodPost,
+		Path:    protocol.PathQueryOrders,
+		Headers: requestHeaders(queryReq),
+		Body:    queryReq,
+
+		ResponseType: (*types.Response[protocol.QueryOrdersResp])(nil),
+	})
+	if err != nil {
+		return nil, false
 	}
-
-	// Build request
-	httpReq := &types.HttpRequest{
-		Method: http.MethodPost,
-		Path:   "/api/trade/queryOrders",
-		Headers: map[string]string{
-			"Authorization": s.GetAuthorizationHeader(),
-			"Test":          req.Test, // Pass test flags if any
-		},
-		Body: req, // Use the entire request structure
+	orders, err := decodeResponse[protocol.QueryOrdersResp](protocol.PathQueryOrders, resp, s.config)
+	if err != nil || len(orders.Orders) == 0 {
+		return nil, false
 	}
-
-	// Send request
-	resp, err := s.transport.Do(ctx, httpReq)
+	body, err := sonic.Marshal(&types.Response[protocol.BookResp]{
+		Data: &protocol.BookResp{HotelOrder: orders.Orders[0]},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("get hotel rates request failed: %w", err)
+		return nil, false
 	}
-
-	return types.NewResponseData[protocol.QueryOrdersResp](resp)
+	return &types.HttpResponse{
+		StatusCode: http.StatusOK,
+		Headers:    http.Header{},
+		Body:       body,
+	}, true
 }
 
-func (s *Client) Cancel(ctx context.Context, req *protocol.CancelReq) (*protocol.CancelResp, error) {
-	// Ensure user is authenticated
-	if err := s.Authenticate(ctx); err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
-	}
-
-	// Build request
-	httpReq := &types.HttpRequest{
-		Method: http.MethodPost,
-		Path:   "/api/trade/cancel",
-		Headers: map[string]string{
-			"Authorization": s.GetAuthorizationHeader(),
-			"Test":          req.Test, // Pass test flags if any
+func (s *Client) QueryOrders(ctx context.Context, req *protocol.QueryOrdersReq, opts ...CallOption) (*protocol.QueryOrdersResp, error) {
+	return call[protocol.QueryOrdersReq, protocol.QueryOrdersResp](ctx, s, endpoint{
+		name:   "QueryOrders",
+		method: http.MethodPost,
+		path:   protocol.PathQueryOrders,
+		attrs: func() []attribute.KeyValue {
+			return []attribute.KeyValue{AttrCustomerReferenceNo.StringSlice(req.CustomerReferenceNos)}
 		},
-		Body: req, // Use the entire request structure
-	}
+	}, req, opts)
+}
 
-	// Send request
-	resp, err := s.transport.Do(ctx, httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("get hotel rates request failed: %w", err)
+func (s *Client) Cancel(ctx context.Context, req *protocol.CancelReq, opts ...CallOption) (*protocol.CancelResp, error) {
+	r, err := call[protocol.CancelReq, protocol.CancelResp](ctx, s, endpoint{
+		name:   "Cancel",
+		method: http.MethodPost,
+		path:   protocol.PathCancel,
+		attrs: func() []attribute.KeyValue {
+			return []attribute.KeyValue{AttrCustomerReferenceNo.String(req.CustomerReferenceNo)}
+		},
+	}, req, opts)
+	if err == nil {
+		s.recordOrderStatus(protocol.PathCancel, r.Status)
 	}
-
-	return types.NewResponseData[protocol.CancelResp](resp)
+	return r, err
 }
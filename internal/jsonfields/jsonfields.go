@@ -0,0 +1,122 @@
+// Package jsonfields lists the fields of a struct as encoding/json encodes them
+package jsonfields
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Field is a struct field encoded by encoding/json. Its Index is the path from the struct of Of, as
+// for reflect.Type.FieldByIndex.
+type Field struct {
+	reflect.StructField
+	Name   string // JSON name
+	Depth  int    // embedding depth
+	Tagged bool   // named by its json tag
+}
+
+// cache holds the fields of every struct type seen
+var cache sync.Map // reflect.Type -> []Field
+
+// Of returns the encoded fields of a struct, in order, following the rules of encoding/json for
+// embedded structs: the shallowest field of a name wins, then the one named by its json tag; the
+// others are dropped.
+func Of(t reflect.Type) []Field {
+	if fields, ok := cache.Load(t); ok {
+		return fields.([]Field)
+	}
+	var all []Field
+	collect(t, nil, map[reflect.Type]bool{}, &all)
+
+	byName := make(map[string][]Field)
+	var names []string
+	for _, f := range all {
+		if _, ok := byName[f.Name]; !ok {
+			names = append(names, f.Name)
+		}
+		byName[f.Name] = append(byName[f.Name], f)
+	}
+	var fields []Field
+	for _, name := range names {
+		if f, ok := dominant(byName[name]); ok {
+			fields = append(fields, f)
+		}
+	}
+	cache.Store(t, fields)
+	return fields
+}
+
+// Lookup returns the field a JSON member decodes to: the field of the same name, else of the same
+// name up to case, as encoding/json does
+func Lookup(fields []Field, key string) (Field, bool) {
+	for _, f := range fields {
+		if f.Name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, key) {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+func collect(t reflect.Type, index []int, visiting map[reflect.Type]bool, fields *[]Field) {
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		f.Index = append(index[:len(index):len(index)], i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			collect(ft, f.Index, visiting, fields)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		tagged := name != ""
+		if !tagged {
+			name = f.Name
+		}
+		*fields = append(*fields, Field{StructField: f, Name: name, Depth: len(index), Tagged: tagged})
+	}
+}
+
+// dominant returns the field of a name that encoding/json encodes, if any
+func dominant(fields []Field) (Field, bool) {
+	depth := fields[0].Depth
+	for _, f := range fields {
+		depth = min(depth, f.Depth)
+	}
+	var shallowest, tagged []Field
+	for _, f := range fields {
+		if f.Depth == depth {
+			shallowest = append(shallowest, f)
+			if f.Tagged {
+				tagged = append(tagged, f)
+			}
+		}
+	}
+	if len(shallowest) == 1 {
+		return shallowest[0], true
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return Field{}, false
+}
@@ -0,0 +1,37 @@
+package jsonfields
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOf(t *testing.T) {
+	type inner struct {
+		A string `json:"a"`
+		B string `json:"b"`
+	}
+	type other struct {
+		B string `json:"b"`
+		C string
+	}
+	type outer struct {
+		inner
+		*other
+		A       string `json:"a"`
+		Skipped string `json:"-"`
+		private string
+	}
+	var names []string
+	var indexes [][]int
+	for _, f := range Of(reflect.TypeOf(outer{})) {
+		names = append(names, f.Name)
+		indexes = append(indexes, f.Index)
+	}
+	// a of outer hides a of inner, b is ambiguous
+	if want := []string{"a", "C"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected fields %v, got %v", want, names)
+	}
+	if want := [][]int{{2}, {1, 1}}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("Expected indexes %v, got %v", want, indexes)
+	}
+}
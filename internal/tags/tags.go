@@ -0,0 +1,75 @@
+// Package tags reads the struct tags of the protocol types, shared by the client, the request
+// defaults and the OpenAPI documents
+package tags
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Default returns the default of a field, from its default tag or its json default option
+func Default(f reflect.StructField) (string, bool) {
+	if v, ok := f.Tag.Lookup("default"); ok {
+		return v, true
+	}
+	_, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+	for _, opt := range strings.Split(opts, ",") {
+		if v, ok := strings.CutPrefix(opt, "default="); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Header is a field tagged `api.header:"Name"`. Its Index is the path from the struct of Headers,
+// as for reflect.Value.FieldByIndexErr.
+type Header struct {
+	Name  string
+	Field reflect.StructField
+	Index []int
+}
+
+// cache holds the headers of every struct type seen
+var cache sync.Map // reflect.Type -> []Header
+
+// Headers returns the fields tagged `api.header:"Name"` of a struct and of the structs it embeds or
+// holds, such as CurrencyOption, SessionOption, TestOption or CommonHeader; the first one of a name
+// wins. Slices and maps are not walked.
+func Headers(t reflect.Type) []Header {
+	if headers, ok := cache.Load(t); ok {
+		return headers.([]Header)
+	}
+	var headers []Header
+	collect(t, nil, map[reflect.Type]bool{}, map[string]bool{}, &headers)
+	cache.Store(t, headers)
+	return headers
+}
+
+func collect(t reflect.Type, index []int, visiting map[reflect.Type]bool, seen map[string]bool, headers *[]Header) {
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		path := append(append([]int(nil), index...), i)
+		if name := f.Tag.Get("api.header"); name != "" {
+			if f.IsExported() && !seen[name] {
+				seen[name] = true
+				*headers = append(*headers, Header{Name: name, Field: f, Index: path})
+			}
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		// Exported fields of embedded unexported structs are reachable, as in encoding/json
+		if ft.Kind() == reflect.Struct && (f.IsExported() || f.Anonymous) {
+			collect(ft, path, visiting, seen, headers)
+		}
+	}
+}
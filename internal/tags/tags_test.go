@@ -0,0 +1,45 @@
+package tags
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	type req struct {
+		A string `json:"a" default:"x"`
+		B int    `json:"b,omitempty,default=10"`
+		C string `json:"c"`
+	}
+	rt := reflect.TypeOf(req{})
+	for i, want := range []string{"x", "10", ""} {
+		if got, ok := Default(rt.Field(i)); got != want || ok != (want != "") {
+			t.Errorf("Expected default %q of %s, got %q, %v", want, rt.Field(i).Name, got, ok)
+		}
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	type option struct {
+		Currency string `api.header:"Currency"`
+	}
+	type nested struct {
+		Session string `api.header:"Session-Id"`
+	}
+	type req struct {
+		option
+		Nested   *nested
+		Other    string `api.header:"Currency"`
+		internal string `api.header:"Internal"`
+	}
+	var got []string
+	for _, h := range Headers(reflect.TypeOf(req{})) {
+		got = append(got, h.Name+" "+h.Field.Name)
+		if f := reflect.TypeOf(req{}).FieldByIndex(h.Index); f.Name != h.Field.Name {
+			t.Errorf("Unexpected index %v of %s", h.Index, h.Name)
+		}
+	}
+	if want := []string{"Currency Currency", "Session-Id Session"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected headers %v, got %v", want, got)
+	}
+}
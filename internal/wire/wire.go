@@ -0,0 +1,46 @@
+// Package wire holds the rules the protocol types follow in JSON, shared by the contract check of the
+// responses and the JSON Schemas, so that both accept the same payloads
+package wire
+
+import (
+	"reflect"
+)
+
+// Scalar is a type encoded otherwise than its kind tells
+type Scalar struct {
+	// JSONTypes are the JSON types it decodes from, the one it encodes to first; none accepts any.
+	// The items of an array are checked as the elements of the type.
+	JSONTypes   []string
+	Format      string // JSON Schema format, e.g. "date"
+	Description string
+}
+
+// typesPkg is the package of the protocol types, which imports this one
+const typesPkg = "github.com/hotelbyte-com/sdk-go/protocol/types"
+
+// scalars by package path and type name, as their UnmarshalJSON methods accept them
+var scalars = map[string]Scalar{
+	typesPkg + ".DateInt": {JSONTypes: []string{"string", "number"}, Format: "date", Description: "date, also accepted as a number such as 20260101"},
+	typesPkg + ".ID":      {JSONTypes: []string{"string", "number"}, Description: "numeric ID, also accepted as a number"},
+	typesPkg + ".IDs": {
+		JSONTypes:   []string{"array", "string", "number"},
+		Description: "numeric IDs, also accepted as a comma-separated string or a single number",
+	},
+	"time.Time":                {JSONTypes: []string{"string"}, Format: "date-time"},
+	"encoding/json.RawMessage": {},
+}
+
+// ScalarOf returns the rules of t if it is encoded otherwise than its kind tells
+func ScalarOf(t reflect.Type) (Scalar, bool) {
+	if t.Name() == "" {
+		return Scalar{}, false
+	}
+	s, ok := scalars[t.PkgPath()+"."+t.Name()]
+	return s, ok
+}
+
+// Required reports whether a field is tagged `required:"true"`: it must be present and not null,
+// whether or not its zero value is omitted
+func Required(f reflect.StructField) bool {
+	return f.Tag.Get("required") == "true"
+}
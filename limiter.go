@@ -0,0 +1,221 @@
+package hotelbyte
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+// Priority orders requests waiting for the same rate limit budget; higher goes first
+type Priority int
+
+const (
+	// PriorityBulk is for background traffic such as static syncs and hotel list crawling
+	PriorityBulk Priority = iota
+	// PriorityNormal is the default
+	PriorityNormal
+	// PriorityHigh is for booking flow calls a customer is waiting on
+	PriorityHigh
+)
+
+// Budget is a token bucket: QPS tokens are added per second, up to Burst
+type Budget struct {
+	QPS   float64
+	Burst int // defaults to 1
+}
+
+// RateLimitConfig represents client-side rate limiting configuration
+type RateLimitConfig struct {
+	// Account is the budget shared by every endpoint, e.g. the QPS of the AppKey; nil means unlimited
+	Account *Budget
+	// Endpoints are budgets of single paths, applied in addition to Account
+	Endpoints map[string]Budget
+	// Priorities sets the Priority per path; paths not listed are PriorityNormal
+	Priorities map[string]Priority
+}
+
+// DefaultRatePriorities returns the default priorities: booking flow calls jump ahead of bulk searches
+func DefaultRatePriorities() map[string]Priority {
+	return map[string]Priority{
+		protocol.PathHotelList:  PriorityBulk,
+		protocol.PathCheckAvail: PriorityHigh,
+		protocol.PathBook:       PriorityHigh,
+		protocol.PathCancel:     PriorityHigh,
+	}
+}
+
+// rateLimiter blocks requests until the budgets of their endpoint allow them
+type rateLimiter struct {
+	account    *tokenBucket
+	endpoints  map[string]*tokenBucket
+	priorities map[string]Priority
+}
+
+// newRateLimiter returns nil if no budget is configured
+func newRateLimiter(c RateLimitConfig) *rateLimiter {
+	if c.Account == nil && len(c.Endpoints) == 0 {
+		return nil
+	}
+	l := &rateLimiter{
+		endpoints:  make(map[string]*tokenBucket, len(c.Endpoints)),
+		priorities: c.Priorities,
+	}
+	if c.Account != nil {
+		l.account = newTokenBucket(*c.Account)
+	}
+	for path, budget := range c.Endpoints {
+		l.endpoints[path] = newTokenBucket(budget)
+	}
+	return l
+}
+
+// wait blocks until the request to path may be sent, or ctx is done
+func (l *rateLimiter) wait(ctx context.Context, path string) error {
+	if l == nil {
+		return nil
+	}
+	priority, ok := l.priorities[path]
+	if !ok {
+		priority = PriorityNormal
+	}
+	if b := l.endpoints[path]; b != nil {
+		if err := b.wait(ctx, priority); err != nil {
+			return err
+		}
+	}
+	if l.account != nil {
+		return l.account.wait(ctx, priority)
+	}
+	return nil
+}
+
+// tokenBucket hands out tokens to waiters by priority, then in arrival order
+type tokenBucket struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	waiters waiterQueue
+	seq     uint64
+	timer   *time.Timer
+}
+
+func newTokenBucket(b Budget) *tokenBucket {
+	burst := float64(b.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   b.QPS,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) wait(ctx context.Context, priority Priority) error {
+	b.mu.Lock()
+	b.refillLocked(time.Now())
+	if len(b.waiters) == 0 && b.tokens >= 1 {
+		b.tokens--
+		b.mu.Unlock()
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	w := &waiter{priority: priority, seq: b.seq, ready: make(chan struct{})}
+	b.seq++
+	heap.Push(&b.waiters, w)
+	b.dispatchLocked(time.Now())
+	b.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if w.index < 0 {
+			// Granted meanwhile; hand the token back to the next waiter
+			b.tokens++
+			b.dispatchLocked(time.Now())
+		} else {
+			heap.Remove(&b.waiters, w.index)
+		}
+		return ctx.Err()
+	}
+}
+
+func (b *tokenBucket) refillLocked(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// dispatchLocked grants available tokens to the first waiters and schedules the next grant
+func (b *tokenBucket) dispatchLocked(now time.Time) {
+	b.refillLocked(now)
+	for len(b.waiters) > 0 && b.tokens >= 1 {
+		w := heap.Pop(&b.waiters).(*waiter)
+		b.tokens--
+		close(w.ready)
+	}
+	if len(b.waiters) > 0 && b.timer == nil && b.rate > 0 {
+		d := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.timer = time.AfterFunc(d, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.timer = nil
+			b.dispatchLocked(time.Now())
+		})
+	}
+}
+
+type waiter struct {
+	priority Priority
+	seq      uint64
+	ready    chan struct{}
+	index    int // position in the queue, -1 once granted or removed
+}
+
+// waiterQueue is a heap of waiters, highest priority first, then first come first served
+type waiterQueue []*waiter
+
+func (q waiterQueue) Len() int { return len(q) }
+
+func (q waiterQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waiterQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waiterQueue) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waiterQueue) Pop() any {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*q = old[:n-1]
+	return w
+}
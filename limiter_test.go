@@ -0,0 +1,72 @@
+package hotelbyte
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketPriority(t *testing.T) {
+	b := newTokenBucket(Budget{QPS: 10, Burst: 1})
+	if err := b.wait(context.Background(), PriorityNormal); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+
+	var (
+		mu    sync.Mutex
+		order []Priority
+		wg    sync.WaitGroup
+	)
+	start := func(p Priority) {
+		b.mu.Lock()
+		queued := len(b.waiters)
+		b.mu.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := b.wait(context.Background(), p); err != nil {
+				t.Errorf("wait failed: %v", err)
+				return
+			}
+			mu.Lock()
+			order = append(order, p)
+			mu.Unlock()
+		}()
+		// let the waiter enqueue before the next one arrives
+		for {
+			b.mu.Lock()
+			n := len(b.waiters)
+			b.mu.Unlock()
+			if n > queued {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	start(PriorityBulk)
+	start(PriorityBulk)
+	start(PriorityHigh)
+	wg.Wait()
+
+	if len(order) != 3 || order[0] != PriorityHigh {
+		t.Errorf("Expected the high priority waiter first, got %v", order)
+	}
+}
+
+func TestTokenBucketRespectsContext(t *testing.T) {
+	b := newTokenBucket(Budget{QPS: 0.001, Burst: 1})
+	if err := b.wait(context.Background(), PriorityNormal); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.wait(ctx, PriorityHigh); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if len(b.waiters) != 0 {
+		t.Errorf("Expected the abandoned waiter to be removed, got %d waiters", len(b.waiters))
+	}
+}
@@ -0,0 +1,218 @@
+package hotelbyte
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bytedance/sonic"
+	"github.com/sirupsen/logrus"
+)
+
+// Level is the severity of a log entry; the values match slog levels
+type Level int
+
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+func (l Level) String() string {
+	return slog.Level(l).String()
+}
+
+// Field is a key-value pair attached to a log entry
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Logger receives the log entries of the SDK. Adapters are provided for slog and logrus.
+// Implementations must be safe for concurrent use.
+type Logger interface {
+	// Enabled reports whether entries of the level are logged, so that building them can be skipped
+	Enabled(ctx context.Context, level Level) bool
+	// Log writes an entry
+	Log(ctx context.Context, level Level, msg string, fields ...Field)
+}
+
+// NopLogger discards every entry; it is the default Logger
+type NopLogger struct{}
+
+func (NopLogger) Enabled(context.Context, Level) bool          { return false }
+func (NopLogger) Log(context.Context, Level, string, ...Field) {}
+
+// SlogLogger writes entries to a *slog.Logger
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a Logger writing to logger, or to slog.Default() if nil
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogLogger{logger: logger}
+}
+
+func (l *SlogLogger) Enabled(ctx context.Context, level Level) bool {
+	return l.logger.Enabled(ctx, slog.Level(level))
+}
+
+func (l *SlogLogger) Log(ctx context.Context, level Level, msg string, fields ...Field) {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	l.logger.LogAttrs(ctx, slog.Level(level), msg, attrs...)
+}
+
+// LogrusLogger writes entries to a logrus logger or entry
+type LogrusLogger struct {
+	logger logrus.FieldLogger
+}
+
+// NewLogrusLogger returns a Logger writing to logger, or to the logrus standard logger if nil
+func NewLogrusLogger(logger logrus.FieldLogger) *LogrusLogger {
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+	return &LogrusLogger{logger: logger}
+}
+
+func (l *LogrusLogger) Enabled(_ context.Context, level Level) bool {
+	return l.logger.WithFields(nil).Logger.IsLevelEnabled(logrusLevel(level))
+}
+
+func (l *LogrusLogger) Log(ctx context.Context, level Level, msg string, fields ...Field) {
+	data := make(logrus.Fields, len(fields))
+	for _, f := range fields {
+		data[f.Key] = f.Value
+	}
+	l.logger.WithFields(data).WithContext(ctx).Log(logrusLevel(level), msg)
+}
+
+func logrusLevel(level Level) logrus.Level {
+	switch {
+	case level >= LevelError:
+		return logrus.ErrorLevel
+	case level >= LevelWarn:
+		return logrus.WarnLevel
+	case level >= LevelInfo:
+		return logrus.InfoLevel
+	default:
+		return logrus.DebugLevel
+	}
+}
+
+// Fields of request log entries, see LoggingConfig.Fields
+const (
+	LogFieldEndpoint   = "endpoint"
+	LogFieldMethod     = "method"
+	LogFieldStatus     = "status"
+	LogFieldLatency    = "latency"
+	LogFieldAttempt    = "attempt"
+	LogFieldTraceID    = "trace_id"   // Trace-Id response header
+	LogFieldSessionID  = "session_id" // Session-Id response header
+	LogFieldServerCost = "server_cost_ms"
+)
+
+// logFields lists every known field
+var logFields = map[string]bool{
+	LogFieldEndpoint:   true,
+	LogFieldMethod:     true,
+	LogFieldStatus:     true,
+	LogFieldLatency:    true,
+	LogFieldAttempt:    true,
+	LogFieldTraceID:    true,
+	LogFieldSessionID:  true,
+	LogFieldServerCost: true,
+}
+
+// DefaultLogFields returns the fields logged by default
+func DefaultLogFields() []string {
+	return []string{LogFieldEndpoint, LogFieldStatus, LogFieldLatency, LogFieldTraceID}
+}
+
+// DefaultRedactKeys returns the JSON keys whose values are masked in logged bodies besides the PII
+// fields of their types, for the bodies of unknown type
+func DefaultRedactKeys() []string {
+	return []string{"appKey", "appSecret", "ticket", "token", "authorization", "password"}
+}
+
+// LoggingConfig represents logging configuration
+type LoggingConfig struct {
+	// Logger receives the entries; nil or NopLogger logs nothing
+	Logger Logger
+	// Level is the level of requests answered with a 2xx or 3xx status
+	Level Level
+	// ErrorLevel is the level of requests that failed or were answered with a 4xx or 5xx status
+	ErrorLevel Level
+	// Fields are the fields of request entries, see the LogField constants
+	Fields []string
+	// Bodies logs request and response bodies with sensitive values redacted
+	Bodies bool
+	// MaxBodyBytes truncates logged bodies; 0 means no limit
+	MaxBodyBytes int
+	// Redactor redacts the PII fields of the bodies, responses by their types.HttpRequest.ResponseType,
+	// and the values of its RedactConfig.Keys; nil masks them, with DefaultRedactKeys
+	Redactor *Redactor
+}
+
+// enabled reports whether any entry may be logged
+func (c LoggingConfig) enabled() bool {
+	if c.Logger == nil {
+		return false
+	}
+	_, nop := c.Logger.(NopLogger)
+	return !nop
+}
+
+// logRedactor masks the PII fields and the values of DefaultRedactKeys
+var logRedactor = NewRedactor(RedactConfig{Keys: DefaultRedactKeys()})
+
+// redactBody marshals a body for logging with its PII fields redacted: typed bodies by their type,
+// raw ones as JSON of the type of like
+func (c LoggingConfig) redactBody(body any, like any) string {
+	redactor := c.Redactor
+	if redactor == nil {
+		redactor = logRedactor
+	}
+	var raw []byte
+	switch b := body.(type) {
+	case nil:
+		return ""
+	case []byte:
+		raw = b
+	case string:
+		raw = []byte(b)
+	default:
+		v, err := redactor.Value(b)
+		if err != nil {
+			return "<unmarshalable body>"
+		}
+		if raw, err = sonic.Marshal(v); err != nil {
+			return "<unmarshalable body>"
+		}
+		return c.truncate(raw)
+	}
+	if len(raw) == 0 {
+		return ""
+	}
+	redacted, err := redactor.RedactJSON(raw, like)
+	if err != nil {
+		// Not JSON: nothing can be redacted, so nothing is shown
+		return "<non-JSON body>"
+	}
+	return c.truncate(redacted)
+}
+
+// truncate cuts a body to MaxBodyBytes
+func (c LoggingConfig) truncate(body []byte) string {
+	s := string(body)
+	if c.MaxBodyBytes > 0 && len(s) > c.MaxBodyBytes {
+		s = s[:c.MaxBodyBytes] + "...(truncated)"
+	}
+	return s
+}
@@ -0,0 +1,116 @@
+package hotelbyte
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+func TestLogRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case protocol.PathAuthTicket:
+			fmt.Fprint(w, `{"code":0,"data":{"ticket":"secret-ticket"}}`)
+		case protocol.PathBook:
+			w.Header().Set("Trace-Id", "server-trace")
+			fmt.Fprint(w, `{"code":0,"data":{"hotelOrder":{"status":2,"holder":{"firstName":"John"},`+
+				`"rooms":[{"guests":[{"firstName":"Jane","nationalityCode":"US"}]}]}}}`)
+		}
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := NewClient(
+		WithBaseURL(srv.URL),
+		WithCredentials("key", "secret"),
+		WithLogger(NewSlogLogger(logger)),
+		WithLogLevels(LevelInfo, LevelWarn),
+		WithBodyLogging(0),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.Book(context.Background(), &protocol.BookReq{
+		CustomerReferenceNo: "ref-1",
+		RatePkgId:           "pkg-1",
+		Holder:              protocol.Holder{FirstName: "John", LastName: "Doe", Email: "john@example.com"},
+		Guests:              []protocol.Guest{{RoomIndex: 1, FirstName: "John", LastName: "Doe"}},
+		SessionOption:       protocol.SessionOption{SessionId: "session-1"},
+	})
+	if err != nil {
+		t.Fatalf("Book failed: %v", err)
+	}
+
+	if out := buf.String(); strings.Contains(out, "secret-ticket") || strings.Contains(out, "john@example.com") ||
+		strings.Contains(out, `"secret"`) {
+		t.Errorf("Expected secrets to be redacted, got %s", out)
+	}
+
+	var book map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if entry[LogFieldEndpoint] == protocol.PathBook {
+			book = entry
+		}
+	}
+	if book == nil {
+		t.Fatalf("Expected a log entry of %s, got %s", protocol.PathBook, buf.String())
+	}
+	if book["level"] != "INFO" || book[LogFieldStatus] != float64(200) || book[LogFieldTraceID] != "server-trace" {
+		t.Errorf("Unexpected entry %v", book)
+	}
+	if _, ok := book[LogFieldLatency]; !ok {
+		t.Errorf("Expected latency in entry %v", book)
+	}
+	if body, _ := book["request_body"].(string); !strings.Contains(body, `"customerReferenceNo":"ref-1"`) ||
+		!strings.Contains(body, `"firstName":"J***"`) {
+		t.Errorf("Unexpected request body %q", body)
+	}
+	// Responses are redacted by their type
+	if body, _ := book["response_body"].(string); !strings.Contains(body, `"firstName":"J***"`) ||
+		!strings.Contains(body, `"nationalityCode":"U***"`) || strings.Contains(body, "Jane") {
+		t.Errorf("Unexpected response body %q", body)
+	}
+}
+
+func TestLogRequestsLevel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":0,"data":{}}`)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil)) // Info and above
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"), WithLogger(NewSlogLogger(logger)))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/ping"}); err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected successful requests to be logged at debug level, got %s", buf.String())
+	}
+}
+
+func TestWithLogFieldsUnknown(t *testing.T) {
+	if _, err := NewClient(WithCredentials("key", "secret"), WithLogFields("nope")); err == nil {
+		t.Error("Expected an error for an unknown log field")
+	}
+}
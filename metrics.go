@@ -0,0 +1,119 @@
+package hotelbyte
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// MetricsRecorder receives the metrics of the SDK. It is small enough to be backed by any metrics
+// library, e.g. Prometheus counter and histogram vectors looked up by name, with the label keys below.
+// Implementations must be safe for concurrent use.
+type MetricsRecorder interface {
+	// IncCounter adds 1 to the counter
+	IncCounter(name string, labels Labels)
+	// ObserveHistogram records a sample of the histogram
+	ObserveHistogram(name string, value float64, labels Labels)
+}
+
+// Labels are the label values of a sample, keyed by label name
+type Labels map[string]string
+
+// Metric names, following Prometheus conventions
+const (
+	// MetricCallsTotal counts Client method calls; labels: endpoint, result
+	MetricCallsTotal = "hotelbyte_calls_total"
+	// MetricCallDuration is the duration of Client method calls in seconds, retries included; labels: endpoint, result
+	MetricCallDuration = "hotelbyte_call_duration_seconds"
+	// MetricRequestsTotal counts HTTP attempts; labels: endpoint, status, attempt
+	MetricRequestsTotal = "hotelbyte_requests_total"
+	// MetricRequestDuration is the client-side latency of HTTP attempts in seconds; labels: endpoint, status
+	MetricRequestDuration = "hotelbyte_request_duration_seconds"
+	// MetricServerDuration is the server-side latency from Server-Cost-Milliseconds in seconds; labels: endpoint
+	MetricServerDuration = "hotelbyte_server_duration_seconds"
+	// MetricRetriesTotal counts HTTP attempts after the first; labels: endpoint, attempt
+	MetricRetriesTotal = "hotelbyte_retries_total"
+	// MetricBizErrorsTotal counts business errors; labels: endpoint, code
+	MetricBizErrorsTotal = "hotelbyte_biz_errors_total"
+	// MetricOrderOutcomesTotal counts the order status returned by Book and Cancel; labels: endpoint, order_status
+	MetricOrderOutcomesTotal = "hotelbyte_order_outcomes_total"
+	// MetricCheckAvailOutcomesTotal counts the status returned by CheckAvail; labels: check_avail_status
+	MetricCheckAvailOutcomesTotal = "hotelbyte_check_avail_outcomes_total"
+)
+
+// Metric label names
+const (
+	LabelEndpoint         = "endpoint"
+	LabelResult           = "result" // ok, biz_error, invalid (rejected by protocol.Validate) or error
+	LabelStatus           = "status" // HTTP status code, or "error" if no response was received
+	LabelAttempt          = "attempt"
+	LabelCode             = "code"
+	LabelOrderStatus      = "order_status"
+	LabelCheckAvailStatus = "check_avail_status"
+)
+
+// nopMetrics discards every metric
+type nopMetrics struct{}
+
+func (nopMetrics) IncCounter(string, Labels)                {}
+func (nopMetrics) ObserveHistogram(string, float64, Labels) {}
+
+// metricsMiddleware records every HTTP attempt
+func metricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+			elapsed := time.Since(start)
+
+			attempt := strconv.Itoa(AttemptFromContext(ctx))
+			status := "error"
+			if resp != nil {
+				status = strconv.Itoa(resp.StatusCode)
+			}
+			recorder.IncCounter(MetricRequestsTotal, Labels{LabelEndpoint: req.Path, LabelStatus: status, LabelAttempt: attempt})
+			recorder.ObserveHistogram(MetricRequestDuration, elapsed.Seconds(), Labels{LabelEndpoint: req.Path, LabelStatus: status})
+			if attempt != "1" {
+				recorder.IncCounter(MetricRetriesTotal, Labels{LabelEndpoint: req.Path, LabelAttempt: attempt})
+			}
+			if resp != nil {
+				if ms, err := strconv.ParseInt(resp.Headers.Get("Server-Cost-Milliseconds"), 10, 64); err == nil {
+					recorder.ObserveHistogram(MetricServerDuration, float64(ms)/1000, Labels{LabelEndpoint: req.Path})
+				}
+			}
+			return resp, err
+		}
+	}
+}
+
+// recordCall records the outcome of a Client method call
+func (s *Client) recordCall(endpoint string, start time.Time, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+		var validationErr *protocol.ValidationError
+		if errors.As(err, &validationErr) {
+			result = "invalid"
+		} else if bizErr, ok := types.CastBizErr(err); ok {
+			result = "biz_error"
+			s.metrics.IncCounter(MetricBizErrorsTotal, Labels{LabelEndpoint: endpoint, LabelCode: strconv.Itoa(int(bizErr.Code))})
+		}
+	}
+	labels := Labels{LabelEndpoint: endpoint, LabelResult: result}
+	s.metrics.IncCounter(MetricCallsTotal, labels)
+	s.metrics.ObserveHistogram(MetricCallDuration, time.Since(start).Seconds(), labels)
+}
+
+// recordOrderStatus records the order status returned by Book or Cancel
+func (s *Client) recordOrderStatus(endpoint string, status protocol.OrderStatus) {
+	s.metrics.IncCounter(MetricOrderOutcomesTotal, Labels{LabelEndpoint: endpoint, LabelOrderStatus: status.String()})
+}
+
+// recordCheckAvailStatus records the status returned by CheckAvail
+func (s *Client) recordCheckAvailStatus(status protocol.CheckAvailStatus) {
+	s.metrics.IncCounter(MetricCheckAvailOutcomesTotal, Labels{LabelCheckAvailStatus: status.String()})
+}
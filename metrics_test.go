@@ -0,0 +1,137 @@
+package hotelbyte
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+type sample struct {
+	name   string
+	value  float64
+	labels Labels
+}
+
+type fakeRecorder struct {
+	mu         sync.Mutex
+	counters   []sample
+	histograms []sample
+}
+
+func (r *fakeRecorder) IncCounter(name string, labels Labels) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counters = append(r.counters, sample{name: name, value: 1, labels: labels})
+}
+
+func (r *fakeRecorder) ObserveHistogram(name string, value float64, labels Labels) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.histograms = append(r.histograms, sample{name: name, value: value, labels: labels})
+}
+
+// count returns the number of counter increments matching name and labels
+func (r *fakeRecorder) count(name string, labels Labels) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+next:
+	for _, s := range r.counters {
+		if s.name != name {
+			continue
+		}
+		for k, v := range labels {
+			if s.labels[k] != v {
+				continue next
+			}
+		}
+		n++
+	}
+	return n
+}
+
+func TestMetrics(t *testing.T) {
+	var checkAvailCalls int
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case protocol.PathCheckAvail:
+			checkAvailCalls++
+			if checkAvailCalls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Server-Cost-Milliseconds", "250")
+			fmt.Fprint(w, `{"code":0,"data":{"status":1}}`)
+		case protocol.PathBook:
+			fmt.Fprint(w, `{"code":0,"data":{"hotelOrder":{"status":1}}}`)
+		case protocol.PathCancel:
+			fmt.Fprint(w, `{"code":3001,"msg":"cancel failed"}`)
+		}
+	})
+
+	recorder := &fakeRecorder{}
+	client, err := NewClient(
+		WithBaseURL(srv.URL),
+		WithCredentials("key", "secret"),
+		WithRetryConfig(2, 0, 0),
+		WithMetrics(recorder),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.Authenticate(ctx); err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if _, err := client.CheckAvail(ctx, &protocol.CheckAvailReq{RatePkgId: "pkg-1", SessionOption: protocol.SessionOption{SessionId: "session-1"}}); err != nil {
+		t.Fatalf("CheckAvail failed: %v", err)
+	}
+	if _, err := client.Book(ctx, testBookReq()); err != nil {
+		t.Fatalf("Book failed: %v", err)
+	}
+	if _, err := client.Cancel(ctx, &protocol.CancelReq{CustomerReferenceNo: "ref-1", SupplierReferenceNo: "sup-1"}); err == nil {
+		t.Fatal("Expected Cancel to fail")
+	}
+	if _, err := client.Cancel(ctx, &protocol.CancelReq{}); err == nil {
+		t.Fatal("Expected Cancel to be rejected")
+	}
+
+	checks := []struct {
+		name   string
+		labels Labels
+		want   int
+	}{
+		{MetricCallsTotal, Labels{LabelEndpoint: protocol.PathAuthTicket, LabelResult: "ok"}, 1},
+		{MetricRequestsTotal, Labels{LabelEndpoint: protocol.PathCheckAvail, LabelStatus: "503", LabelAttempt: "1"}, 1},
+		{MetricRequestsTotal, Labels{LabelEndpoint: protocol.PathCheckAvail, LabelStatus: "200", LabelAttempt: "2"}, 1},
+		{MetricRetriesTotal, Labels{LabelEndpoint: protocol.PathCheckAvail, LabelAttempt: "2"}, 1},
+		{MetricCallsTotal, Labels{LabelEndpoint: protocol.PathCheckAvail, LabelResult: "ok"}, 1},
+		{MetricCheckAvailOutcomesTotal, Labels{LabelCheckAvailStatus: "available"}, 1},
+		{MetricOrderOutcomesTotal, Labels{LabelEndpoint: protocol.PathBook, LabelOrderStatus: "confirming"}, 1},
+		{MetricCallsTotal, Labels{LabelEndpoint: protocol.PathCancel, LabelResult: "biz_error"}, 1},
+		{MetricBizErrorsTotal, Labels{LabelEndpoint: protocol.PathCancel, LabelCode: "3001"}, 1},
+		{MetricCallsTotal, Labels{LabelEndpoint: protocol.PathCancel, LabelResult: "invalid"}, 1},
+		{MetricOrderOutcomesTotal, Labels{LabelEndpoint: protocol.PathCancel}, 0},
+	}
+	for _, c := range checks {
+		if got := recorder.count(c.name, c.labels); got != c.want {
+			t.Errorf("%s%v: expected %d, got %d", c.name, c.labels, c.want, got)
+		}
+	}
+
+	var serverCost float64
+	for _, s := range recorder.histograms {
+		if s.name == MetricServerDuration && s.labels[LabelEndpoint] == protocol.PathCheckAvail {
+			serverCost = s.value
+		}
+	}
+	if serverCost != 0.25 {
+		t.Errorf("Expected server duration 0.25s, got %v", serverCost)
+	}
+}
@@ -0,0 +1,105 @@
+package hotelbyte
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// RoundTripFunc sends a request and returns its response
+type RoundTripFunc func(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, error)
+
+// Middleware wraps a RoundTripFunc to decorate requests, observe responses or short-circuit calls,
+// e.g. for header rewriting, logging, metrics or fault injection
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Chain composes middlewares into one; the first middleware is the outermost
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// DefaultMiddlewares returns the middlewares installed unless disabled by WithoutDefaultMiddlewares
+func DefaultMiddlewares(logging LoggingConfig) []Middleware {
+	return []Middleware{
+		LogRequests(logging),
+	}
+}
+
+// LogRequests logs every request with the fields of the config; nothing is logged without a Logger
+func LogRequests(config LoggingConfig) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		if !config.enabled() {
+			return next
+		}
+		return func(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+			latency := time.Since(start)
+
+			level := config.Level
+			if err != nil || resp.StatusCode >= http.StatusBadRequest {
+				level = config.ErrorLevel
+			}
+			if !config.Logger.Enabled(ctx, level) {
+				return resp, err
+			}
+
+			fields := make([]Field, 0, len(config.Fields)+3)
+			for _, name := range config.Fields {
+				if v, ok := logField(ctx, name, req, resp, latency); ok {
+					fields = append(fields, Field{Key: name, Value: v})
+				}
+			}
+			if err != nil {
+				fields = append(fields, Field{Key: "error", Value: err.Error()})
+			}
+			if config.Bodies {
+				fields = append(fields, Field{Key: "request_body", Value: config.redactBody(req.Body, nil)})
+				if resp != nil {
+					fields = append(fields, Field{Key: "response_body", Value: config.redactBody(resp.Body, req.ResponseType)})
+				}
+			}
+			config.Logger.Log(ctx, level, "hotelbyte request", fields...)
+			return resp, err
+		}
+	}
+}
+
+// logField returns the value of a request entry field, if known
+func logField(ctx context.Context, name string, req *types.HttpRequest, resp *types.HttpResponse, latency time.Duration) (any, bool) {
+	switch name {
+	case LogFieldEndpoint:
+		return req.Path, true
+	case LogFieldMethod:
+		return req.Method, true
+	case LogFieldLatency:
+		return latency, true
+	case LogFieldAttempt:
+		return AttemptFromContext(ctx), true
+	}
+	if resp == nil {
+		return nil, false
+	}
+	switch name {
+	case LogFieldStatus:
+		return resp.StatusCode, true
+	case LogFieldTraceID:
+		v := resp.Headers.Get("Trace-Id")
+		return v, v != ""
+	case LogFieldSessionID:
+		v := resp.Headers.Get("Session-Id")
+		return v, v != ""
+	case LogFieldServerCost:
+		v, err := strconv.ParseInt(resp.Headers.Get("Server-Cost-Milliseconds"), 10, 64)
+		return v, err == nil
+	}
+	return nil, false
+}
@@ -0,0 +1,59 @@
+package hotelbyte
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	mw := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, error) {
+				calls = append(calls, name)
+				return next(ctx, req)
+			}
+		}
+	}
+	handler := Chain(mw("a"), mw("b"))(func(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, error) {
+		calls = append(calls, "send")
+		return &types.HttpResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	if _, err := handler(context.Background(), &types.HttpRequest{}); err != nil {
+		t.Fatalf("handler failed: %v", err)
+	}
+	if want := []string{"a", "b", "send"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+}
+
+func TestWithMiddlewareShortCircuit(t *testing.T) {
+	fake := func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, error) {
+			return &types.HttpResponse{StatusCode: http.StatusTeapot, Headers: http.Header{}}, nil
+		}
+	}
+	client, err := NewClient(
+		WithBaseURL("http://127.0.0.1:0"),
+		WithCredentials("key", "secret"),
+		WithMiddleware(fake),
+		WithoutDefaultMiddlewares(),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	resp, err := client.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/ping"})
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusTeapot {
+		t.Errorf("Expected status %d, got %d", http.StatusTeapot, resp.StatusCode)
+	}
+}
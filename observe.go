@@ -0,0 +1,33 @@
+package hotelbyte
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// callScope observes one Client method call, with a span and metrics
+type callScope struct {
+	client   *Client
+	span     trace.Span
+	endpoint string
+	start    time.Time
+}
+
+// beginCall starts observing a Client method call; end must be called with its error
+func (s *Client) beginCall(ctx context.Context, name, endpoint string, attrs ...attribute.KeyValue) (context.Context, *callScope) {
+	ctx, span := s.startSpan(ctx, name, endpoint, attrs...)
+	return ctx, &callScope{
+		client:   s,
+		span:     span,
+		endpoint: endpoint,
+		start:    time.Now(),
+	}
+}
+
+func (c *callScope) end(err error) {
+	c.client.recordCall(c.endpoint, c.start, err)
+	endSpan(c.span, err)
+}
@@ -0,0 +1,82 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+const (
+	// JSONSchemaDialect is the dialect of the documents of JSONSchema and JSONSchemas
+	JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+	jsonSchemaRefPrefix = "#/$defs/"
+)
+
+// JSONSchema returns the JSON Schema document of the type of v, e.g. a *protocol.HotelListReq, with
+// the definitions it references under $defs
+func JSONSchema(v any) *Schema {
+	s := newSchemas(jsonSchemaRefPrefix)
+	return s.document(reflect.TypeOf(v))
+}
+
+// JSONSchemas returns the JSON Schema documents of the request and response types of the endpoints,
+// of the types they reference and of the enums, by definition name
+func JSONSchemas(endpoints []protocol.Endpoint) map[string]*Schema {
+	s := newSchemas(jsonSchemaRefPrefix)
+	for t := range enums {
+		s.of(t)
+	}
+	for _, e := range endpoints {
+		s.of(reflect.TypeOf(e.Request))
+		s.of(reflect.TypeOf(e.Response))
+	}
+	docs := make(map[string]*Schema, len(s.names))
+	for t, name := range s.names {
+		docs[name] = s.document(t)
+	}
+	return docs
+}
+
+// document returns the schema of t as a standalone document
+func (s *schemas) document(t reflect.Type) *Schema {
+	doc := s.of(t)
+	if name := strings.TrimPrefix(doc.Ref, s.refPrefix); doc.Ref != "" {
+		// A copy, the definition is shared by the other documents
+		def := *s.defs[name]
+		doc = &def
+		doc.Title = name
+	}
+	doc.Dialect = JSONSchemaDialect
+
+	refs := make(map[string]bool)
+	s.collectRefs(doc, refs)
+	if len(refs) > 0 {
+		doc.Defs = make(map[string]*Schema, len(refs))
+		for name := range refs {
+			doc.Defs[name] = s.defs[name]
+		}
+	}
+	return doc
+}
+
+// collectRefs adds the names of the definitions schema references, directly or not, to refs
+func (s *schemas) collectRefs(schema *Schema, refs map[string]bool) {
+	if schema == nil {
+		return
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, s.refPrefix)
+		if !refs[name] {
+			refs[name] = true
+			s.collectRefs(s.defs[name], refs)
+		}
+		return
+	}
+	for _, p := range schema.Properties {
+		s.collectRefs(p, refs)
+	}
+	s.collectRefs(schema.Items, refs)
+	s.collectRefs(schema.AdditionalProperties, refs)
+}
@@ -0,0 +1,46 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+func TestJSONSchema(t *testing.T) {
+	doc := JSONSchema(&protocol.HotelListReq{})
+	if doc.Dialect != JSONSchemaDialect || doc.Title != "HotelListReq" || doc.Type != "object" {
+		t.Fatalf("Unexpected document %s %s %s", doc.Dialect, doc.Title, doc.Type)
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	// Every reference resolves within the document
+	refs := make(map[string]bool)
+	newSchemas(jsonSchemaRefPrefix).collectRefs(doc, refs)
+	for name := range refs {
+		if doc.Defs[name] == nil {
+			t.Errorf("Expected %s under $defs", name)
+		}
+	}
+	if ids := doc.Properties["hotelIds"]; ids.Type != "array" {
+		t.Errorf("Expected hotelIds to be an array, got %+v", ids)
+	}
+}
+
+func TestJSONSchemas(t *testing.T) {
+	docs := JSONSchemas(protocol.Endpoints)
+	for _, name := range []string{"HotelListReq", "HotelListResp", "Hotel", "HotelOrder", "BoardId", "DestinationType"} {
+		if docs[name] == nil || docs[name].Title != name {
+			t.Errorf("Expected the document of %s", name)
+		}
+	}
+	// Recursive types reference themselves
+	dest := JSONSchema(&protocol.Destination{})
+	if dest.Defs["Destination"] == nil {
+		t.Errorf("Expected Destination to reference itself, got %+v", dest)
+	}
+	if enum := docs["OrderStatus"]; len(enum.Enum) != len(protocol.AllOrderStatuses()) || enum.Defs != nil {
+		t.Errorf("Unexpected OrderStatus document %+v", enum)
+	}
+}
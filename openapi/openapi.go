@@ -0,0 +1,191 @@
+// Package openapi generates the OpenAPI 3.1 document of the HotelByte API from the protocol package:
+// the endpoints of protocol.Endpoints, the schemas of their request and response types and the
+// documentation of their struct tags.
+package openapi
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/hotelbyte-com/sdk-go/internal/tags"
+	"github.com/hotelbyte-com/sdk-go/internal/wire"
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+// Version is the OpenAPI version of the documents
+const Version = "3.1.0"
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by lowercase method, e.g. "post"
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]*Header   `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+const (
+	schemaRefPrefix = "#/components/schemas/"
+	ticketScheme    = "ticket"
+	jsonContentType = "application/json"
+)
+
+// Generate returns the OpenAPI document of the endpoints, e.g. protocol.Endpoints
+func Generate(info Info, endpoints []protocol.Endpoint) *Document {
+	s := newSchemas(schemaRefPrefix)
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas: s.defs,
+			SecuritySchemes: map[string]*SecurityScheme{
+				ticketScheme: {Type: "http", Scheme: "bearer", Description: "Ticket of " + protocol.PathAuthTicket},
+			},
+		},
+	}
+	for t := range enums {
+		// Enums are documented even where no endpoint uses them yet
+		s.of(t)
+	}
+	for _, e := range endpoints {
+		item, ok := doc.Paths[e.Path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[e.Path] = item
+		}
+		(*item)[strings.ToLower(e.Method)] = operation(s, e)
+	}
+	return doc
+}
+
+func operation(s *schemas, e protocol.Endpoint) *Operation {
+	reqType := reflect.TypeOf(e.Request).Elem()
+	respType := reflect.TypeOf(e.Response).Elem()
+	op := &Operation{
+		OperationID: e.Name,
+		Summary:     e.Summary,
+		Parameters:  headerParameters(s, reqType),
+		RequestBody: &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{jsonContentType: {Schema: s.of(reqType)}},
+		},
+		Responses: map[string]*Response{
+			"200": {
+				Description: "The data, or a business error code with data absent",
+				Headers:     responseHeaders(s, respType),
+				Content:     map[string]MediaType{jsonContentType: {Schema: envelope(s.of(respType))}},
+			},
+		},
+		Security: []map[string][]string{{ticketScheme: {}}},
+	}
+	if parts := strings.Split(e.Path, "/"); len(parts) > 2 {
+		// e.g. "search" of /api/search/hotelList
+		op.Tags = []string{parts[2]}
+	}
+	if e.Public {
+		op.Security = []map[string][]string{}
+	}
+	return op
+}
+
+// envelope returns the schema of the response envelope around data, see types.Response
+func envelope(data *Schema) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code": {Type: "integer", Format: "int32", Description: "business error code, 0 on success"},
+			"msg":  {Type: "string"},
+			"data": data,
+		},
+		Required: []string{"code"},
+	}
+}
+
+// headerParameters returns the header parameters of a request type
+func headerParameters(s *schemas, t reflect.Type) []*Parameter {
+	var params []*Parameter
+	for _, h := range tags.Headers(t) {
+		params = append(params, &Parameter{
+			Name:     h.Name,
+			In:       "header",
+			Required: wire.Required(h.Field),
+			Schema:   s.field(h.Field),
+		})
+	}
+	return params
+}
+
+// responseHeaders returns the headers of a response type
+func responseHeaders(s *schemas, t reflect.Type) map[string]*Header {
+	fields := tags.Headers(t)
+	if len(fields) == 0 {
+		return nil
+	}
+	headers := make(map[string]*Header, len(fields))
+	for _, h := range fields {
+		headers[h.Name] = &Header{Schema: s.field(h.Field)}
+	}
+	return headers
+}
@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+func TestGenerate(t *testing.T) {
+	doc := Generate(Info{Title: "HotelByte API", Version: "1.0.0"}, protocol.Endpoints)
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" || len(doc.Paths) != len(protocol.Endpoints) {
+		t.Fatalf("Expected an OpenAPI 3.1 document of %d paths, got %s with %d", len(protocol.Endpoints), doc.OpenAPI, len(doc.Paths))
+	}
+
+	book := (*doc.Paths[protocol.PathBook])["post"]
+	if book == nil || book.OperationID != "Book" || book.Tags[0] != "trade" || len(book.Security) != 1 {
+		t.Fatalf("Unexpected Book operation %+v", book)
+	}
+	var headers []string
+	for _, p := range book.Parameters {
+		if p.In == "header" {
+			headers = append(headers, p.Name)
+		}
+	}
+	if want := []string{"Currency", "Session-Id", "Test"}; !reflect.DeepEqual(headers, want) {
+		t.Errorf("Expected header parameters %v, got %v", want, headers)
+	}
+	resp := book.Responses["200"]
+	if resp.Headers["Trace-Id"] == nil || resp.Content[jsonContentType].Schema.Properties["data"].Ref != schemaRefPrefix+"BookResp" {
+		t.Errorf("Unexpected Book response %+v", resp)
+	}
+	if auth := (*doc.Paths[protocol.PathAuthTicket])["post"]; len(auth.Security) != 0 {
+		t.Errorf("Expected no security on %s, got %v", protocol.PathAuthTicket, auth.Security)
+	}
+
+	schemas := doc.Components.Schemas
+	for name, want := range map[string]int{"BoardId": len(protocol.AllBoardIds()), "OrderStatus": 6, "RefundableMode": 3, "DestinationType": 13} {
+		if got := len(schemas[name].Enum); got != want {
+			t.Errorf("Expected %d values of %s, got %d", want, name, got)
+		}
+	}
+	if names := schemas["OrderStatus"].EnumNames; names[2] != "confirmed" {
+		t.Errorf("Unexpected OrderStatus names %v", names)
+	}
+
+	list := schemas["HotelListReq"]
+	if !reflect.DeepEqual(list.Required, []string{"checkIn", "checkOut", "countryCode", "nationalityCode", "residencyCode"}) {
+		t.Errorf("Unexpected required fields %v", list.Required)
+	}
+	checkIn := list.Properties["checkIn"]
+	if checkIn.Type != "string" || checkIn.Format != "date" || checkIn.Default != "now()" || checkIn.Examples[0] != "2026-01-01" {
+		t.Errorf("Unexpected checkIn %+v", checkIn)
+	}
+	if pageSize := list.Properties["pageSize"]; pageSize.Default != float64(10) {
+		t.Errorf("Expected pageSize to default to 10, got %v", pageSize.Default)
+	}
+	if ids := list.Properties["hotelIds"]; ids.Type != "array" || len(ids.Examples[0].([]any)) != 2 {
+		t.Errorf("Unexpected hotelIds %+v", ids)
+	}
+
+	// The fields of embedded structs are flattened, and the status of an order is an enum
+	order := schemas["HotelOrder"]
+	if order.Properties["status"].Ref != schemaRefPrefix+"OrderStatus" || order.Properties["status"].Examples[0] != float64(1) {
+		t.Errorf("Unexpected status %+v", order.Properties["status"])
+	}
+	if order.Properties["supplierReferenceNo"] == nil || order.Properties["netRate"] == nil {
+		t.Errorf("Expected the fields of OrderBasic and Rate, got %v", order.Properties)
+	}
+	if schemas["Holder"].Properties["firstName"].PII != "name" {
+		t.Errorf("Expected the pii kind of the holder name")
+	}
+}
@@ -0,0 +1,205 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hotelbyte-com/sdk-go/internal/jsonfields"
+	"github.com/hotelbyte-com/sdk-go/internal/tags"
+	"github.com/hotelbyte-com/sdk-go/internal/wire"
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+// Schema is a JSON Schema, in the dialect of OpenAPI 3.1
+type Schema struct {
+	Dialect              string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	EnumNames            []string           `json:"x-enum-varnames,omitempty"`
+	EnumDescriptions     []string           `json:"x-enum-descriptions,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+	PII                  string             `json:"x-pii,omitempty"`    // kind of personal data, see the pii tag
+	APIDoc               string             `json:"x-apidoc,omitempty"` // documentation group, see the apidoc tag
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// enum lists the values of an enum type
+type enum struct {
+	values       []any
+	names        []string
+	descriptions []string
+}
+
+func enumOf[T any](values []T, name, describe func(T) string) enum {
+	var e enum
+	for _, v := range values {
+		e.values = append(e.values, v)
+		if name != nil {
+			e.names = append(e.names, name(v))
+		}
+		if describe != nil {
+			e.descriptions = append(e.descriptions, describe(v))
+		}
+	}
+	return e
+}
+
+var enums = map[reflect.Type]enum{
+	// Names are given to the integer enums only, the strings are names of their own
+	reflect.TypeOf(protocol.BoardId("")):        enumOf(protocol.AllBoardIds(), nil, protocol.BoardId.GetNameEn),
+	reflect.TypeOf(protocol.OrderStatus(0)):     enumOf(protocol.AllOrderStatuses(), protocol.OrderStatus.String, nil),
+	reflect.TypeOf(protocol.RefundableMode("")): enumOf(protocol.AllRefundableModes(), nil, nil),
+	reflect.TypeOf(protocol.DestinationType(0)): enumOf(protocol.AllDestinationTypes(), protocol.DestinationType.String, nil),
+}
+
+// schemas builds the schemas of Go types. Named structs and enums are defined once, under refPrefix,
+// and referenced elsewhere.
+type schemas struct {
+	refPrefix string             // e.g. "#/components/schemas/"
+	defs      map[string]*Schema // definitions by name
+	names     map[reflect.Type]string
+}
+
+func newSchemas(refPrefix string) *schemas {
+	return &schemas{refPrefix: refPrefix, defs: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+// of returns the schema of t, a reference if t is a named struct or enum
+func (s *schemas) of(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if scalar, ok := wire.ScalarOf(t); ok {
+		return s.scalar(t, scalar)
+	}
+	if e, ok := enums[t]; ok {
+		return s.define(t, func() *Schema {
+			schema := s.kindOf(t)
+			schema.Enum, schema.EnumNames, schema.EnumDescriptions = e.values, e.names, e.descriptions
+			return schema
+		})
+	}
+	if t.Kind() == reflect.Struct && t.Name() != "" {
+		return s.define(t, func() *Schema { return s.object(t) })
+	}
+	return s.kindOf(t)
+}
+
+// define returns a reference to the definition of t, built once
+func (s *schemas) define(t reflect.Type, build func() *Schema) *Schema {
+	name, ok := s.names[t]
+	if !ok {
+		name = s.name(t)
+		s.names[t] = name
+		// Defined before it is built, for recursive types such as Destination
+		s.defs[name] = &Schema{}
+		*s.defs[name] = *build()
+	}
+	return &Schema{Ref: s.refPrefix + name}
+}
+
+// name returns a definition name of t unique among the others, e.g. "Hotel" or "types.Money" on conflict
+func (s *schemas) name(t reflect.Type) string {
+	name, _, _ := strings.Cut(t.Name(), "[")
+	if _, taken := s.defs[name]; taken {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+	return name
+}
+
+// scalar returns the schema of a type encoded otherwise than its kind tells, of the JSON type it
+// encodes to; the others it decodes from are told by the description
+func (s *schemas) scalar(t reflect.Type, scalar wire.Scalar) *Schema {
+	if len(scalar.JSONTypes) == 0 {
+		// Any value
+		return &Schema{Description: scalar.Description}
+	}
+	schema := &Schema{Type: scalar.JSONTypes[0], Format: scalar.Format, Description: scalar.Description}
+	if schema.Type == "array" {
+		schema.Items = s.of(t.Elem())
+	}
+	return schema
+}
+
+func (s *schemas) kindOf(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		return s.object(t)
+	default:
+		// Interfaces: any value
+		return &Schema{}
+	}
+}
+
+// object returns the schema of a struct, with the fields of its embedded structs as encoding/json does
+func (s *schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, f := range jsonfields.Of(t) {
+		schema.Properties[f.Name] = s.field(f.StructField)
+		if wire.Required(f.StructField) {
+			schema.Required = append(schema.Required, f.Name)
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// field returns the schema of a struct field, with the documentation of its tags
+func (s *schemas) field(f reflect.StructField) *Schema {
+	schema := s.of(f.Type)
+	if v, ok := f.Tag.Lookup("example"); ok {
+		schema.Examples = []any{s.tagValue(schema, v)}
+	}
+	if v, ok := tags.Default(f); ok && v != "" {
+		schema.Default = s.tagValue(schema, v)
+	}
+	schema.PII = f.Tag.Get("pii")
+	schema.APIDoc = f.Tag.Get("apidoc")
+	return schema
+}
+
+// tagValue returns the value of an example or default tag: JSON, except for strings
+func (s *schemas) tagValue(schema *Schema, v string) any {
+	if def, ok := s.defs[strings.TrimPrefix(schema.Ref, s.refPrefix)]; ok && schema.Ref != "" {
+		schema = def
+	}
+	if schema.Type == "string" {
+		return v
+	}
+	var value any
+	if err := json.Unmarshal([]byte(v), &value); err != nil {
+		return v
+	}
+	return value
+}
@@ -30,11 +30,11 @@ type PriceFilter struct {
 }
 
 type AuthReq struct {
-	AppKey    string `json:"appKey"`    // Application key for authentication
-	AppSecret string `json:"appSecret"` // Application secret for authentication
-	TTL       int64  `json:"ttl"`       // Time-to-live in seconds for the token
+	AppKey    string `json:"appKey"`                 // Application key for authentication
+	AppSecret string `json:"appSecret" pii:"secret"` // Application secret for authentication
+	TTL       int64  `json:"ttl"`                    // Time-to-live in seconds for the token
 }
 
 type AuthResp struct {
-	Ticket string `json:"ticket"`
+	Ticket string `json:"ticket" pii:"secret"`
 }
@@ -11,19 +11,20 @@ type BookReq struct {
 	RatePkgId           string  `json:"ratePkgId,omitempty" required:"true"`          // RatePkgId is obtained from HotelStaticDetail API
 	Holder              Holder  `json:"holder,omitzero" required:"true"`              // Holder contains the booking contact information
 	Guests              []Guest `json:"guests,omitzero" required:"true"`              // Guests contains the list of guests for this room
+	CurrencyOption
 	SessionOption
 	TestOption
 }
 type Holder struct {
-	FirstName string `json:"firstName" required:"true" example:"John"`
-	LastName  string `json:"lastName" required:"true" example:"Doe"`
-	Email     string `json:"email,omitempty" required:"false" example:"John@hotelbyte.com"`
+	FirstName string `json:"firstName" required:"true" example:"John" pii:"name"`
+	LastName  string `json:"lastName" required:"true" example:"Doe" pii:"name"`
+	Email     string `json:"email,omitempty" required:"false" example:"John@hotelbyte.com" pii:"email"`
 	Phone     Phone  `json:"phone,omitzero"`
 }
 type Phone struct {
-	CountryCode   string `json:"countryCode,omitempty"`   // AE
-	CountryNumber int64  `json:"countryNumber,omitempty"` // 971
-	Number        string `json:"number,omitempty"`        // 525757249
+	CountryCode   string `json:"countryCode,omitempty"`        // AE
+	CountryNumber int64  `json:"countryNumber,omitempty"`      // 971
+	Number        string `json:"number,omitempty" pii:"phone"` // 525757249
 }
 type BookResp struct {
 	HotelOrder *HotelOrder  `json:"hotelOrder,omitzero"` // HotelOrder contains the hotel order information
@@ -43,6 +44,7 @@ type OrderRoomInfo struct {
 	RoomIndex  int64                 `json:"roomIndex"` // room index
 	Guests     []Guest               `json:"guests"`    // information about all guests in this room
 	RefundInfo []OrderRoomRefundInfo `json:"refundInfo,omitzero"`
+	Extra      types.Extra           `json:"-"` // members the SDK has no field for yet, see types.Extra
 }
 
 // OrderRoomRefundInfo represents refund information for a specific room on a specific date
@@ -58,6 +60,7 @@ type HotelOrder struct {
 	*OrderBasic
 	Hotel *OrderHotelInfo  `json:"hotel,omitempty"` // hotel-specific information
 	Rooms []*OrderRoomInfo `json:"rooms"`           // detailed information for each booked room
+	Extra types.Extra      `json:"-"`               // members the SDK has no field for yet, see types.Extra
 }
 
 // OrderBasic contains the fundamental information about a hotel order
@@ -97,3 +100,32 @@ const (
 	OrderStatus_Failed
 	OrderStatus_CancelFailed
 )
+
+// AllOrderStatuses returns all the known OrderStatus enum values
+func AllOrderStatuses() []OrderStatus {
+	return []OrderStatus{
+		OrderStatus_Unknown,
+		OrderStatus_Confirming,
+		OrderStatus_Confirmed,
+		OrderStatus_Cancelled,
+		OrderStatus_Failed,
+		OrderStatus_CancelFailed,
+	}
+}
+
+func (s OrderStatus) String() string {
+	switch s {
+	case OrderStatus_Confirming:
+		return "confirming"
+	case OrderStatus_Confirmed:
+		return "confirmed"
+	case OrderStatus_Cancelled:
+		return "cancelled"
+	case OrderStatus_Failed:
+		return "failed"
+	case OrderStatus_CancelFailed:
+		return "cancel_failed"
+	default:
+		return "unknown"
+	}
+}
@@ -2,6 +2,7 @@ package protocol
 
 type CheckAvailReq struct {
 	RatePkgId string `json:"ratePkgId" required:"true"`
+	CurrencyOption
 	SessionOption
 	TestOption
 }
@@ -17,3 +18,14 @@ const (
 	CheckAvailStatusAvailable   CheckAvailStatus = 1 // CheckAvailStatusAvailable indicates the room is available for booking
 	CheckAvailStatusUnavailable CheckAvailStatus = 2 // CheckAvailStatusUnavailable indicates the room is not available
 )
+
+func (s CheckAvailStatus) String() string {
+	switch s {
+	case CheckAvailStatusAvailable:
+		return "available"
+	case CheckAvailStatusUnavailable:
+		return "unavailable"
+	default:
+		return "unknown"
+	}
+}
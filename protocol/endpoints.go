@@ -0,0 +1,43 @@
+package protocol
+
+import "net/http"
+
+// API endpoint paths
+const (
+	PathAuthTicket  = "/api/auth/ticket"
+	PathHotelList   = "/api/search/hotelList"
+	PathHotelRates  = "/api/search/hotelRates"
+	PathCheckAvail  = "/api/search/checkAvail"
+	PathBook        = "/api/trade/book"
+	PathQueryOrders = "/api/trade/queryOrders"
+	PathCancel      = "/api/trade/cancel"
+)
+
+// Endpoint describes an API endpoint and the types of its request and response data
+type Endpoint struct {
+	Name     string // e.g. "HotelList"
+	Method   string
+	Path     string
+	Summary  string
+	Request  any  // nil pointer of the request type, e.g. (*HotelListReq)(nil)
+	Response any  // nil pointer of the type of the data in the response envelope
+	Public   bool // callable without a ticket
+}
+
+// Endpoints lists the endpoints of the API
+var Endpoints = []Endpoint{
+	{Name: "AuthTicket", Method: http.MethodPost, Path: PathAuthTicket, Summary: "Get a ticket from the application credentials",
+		Request: (*AuthReq)(nil), Response: (*AuthResp)(nil), Public: true},
+	{Name: "HotelList", Method: http.MethodPost, Path: PathHotelList, Summary: "Search hotels with their lowest rates",
+		Request: (*HotelListReq)(nil), Response: (*HotelListResp)(nil)},
+	{Name: "HotelRates", Method: http.MethodPost, Path: PathHotelRates, Summary: "List the rooms and rates of a hotel",
+		Request: (*HotelRatesReq)(nil), Response: (*HotelRatesResp)(nil)},
+	{Name: "CheckAvail", Method: http.MethodPost, Path: PathCheckAvail, Summary: "Check the availability and price of a rate package",
+		Request: (*CheckAvailReq)(nil), Response: (*CheckAvailResp)(nil)},
+	{Name: "Book", Method: http.MethodPost, Path: PathBook, Summary: "Book a rate package",
+		Request: (*BookReq)(nil), Response: (*BookResp)(nil)},
+	{Name: "QueryOrders", Method: http.MethodPost, Path: PathQueryOrders, Summary: "Query orders",
+		Request: (*QueryOrdersReq)(nil), Response: (*QueryOrdersResp)(nil)},
+	{Name: "Cancel", Method: http.MethodPost, Path: PathCancel, Summary: "Cancel an order",
+		Request: (*CancelReq)(nil), Response: (*CancelResp)(nil)},
+}
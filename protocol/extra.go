@@ -0,0 +1,44 @@
+package protocol
+
+import "github.com/hotelbyte-com/sdk-go/protocol/types"
+
+// The main response types keep the members they have no field for in their Extra, and encode them
+// back, so that services passing them on do not drop the fields added to the API after the SDK
+// release. OrderRoomInfo holds its own, the methods of the RoomRatePkg it embeds would take over
+// its decoding otherwise.
+
+func (h *Hotel) UnmarshalJSON(data []byte) (err error) {
+	h.Extra, err = types.UnmarshalExtra(data, h)
+	return err
+}
+
+func (h Hotel) MarshalJSON() ([]byte, error) {
+	return types.MarshalExtra(&h, h.Extra)
+}
+
+func (p *RoomRatePkg) UnmarshalJSON(data []byte) (err error) {
+	p.Extra, err = types.UnmarshalExtra(data, p)
+	return err
+}
+
+func (p RoomRatePkg) MarshalJSON() ([]byte, error) {
+	return types.MarshalExtra(&p, p.Extra)
+}
+
+func (r *OrderRoomInfo) UnmarshalJSON(data []byte) (err error) {
+	r.Extra, err = types.UnmarshalExtra(data, r)
+	return err
+}
+
+func (r OrderRoomInfo) MarshalJSON() ([]byte, error) {
+	return types.MarshalExtra(&r, r.Extra)
+}
+
+func (o *HotelOrder) UnmarshalJSON(data []byte) (err error) {
+	o.Extra, err = types.UnmarshalExtra(data, o)
+	return err
+}
+
+func (o HotelOrder) MarshalJSON() ([]byte, error) {
+	return types.MarshalExtra(&o, o.Extra)
+}
@@ -0,0 +1,75 @@
+package protocol
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHotelOrderExtra(t *testing.T) {
+	body := `{"status":2,"supplierReferenceNo":"S1","loyalty":{"points":120},` +
+		`"rooms":[{"roomTypeId":"R1","ratePkgId":"pkg-1","roomIndex":1,"upgrade":"suite"}]}`
+	var order HotelOrder
+	if err := json.Unmarshal([]byte(body), &order); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if order.OrderBasic == nil || order.Status != OrderStatus_Confirmed || order.SupplierReferenceNo != "S1" {
+		t.Fatalf("Unexpected order %+v", order.OrderBasic)
+	}
+	room := order.Rooms[0]
+	// The room decodes as a whole, not as the RoomRatePkg it embeds
+	if room.RoomTypeId != "R1" || room.RatePkgId != "pkg-1" || room.RoomIndex != 1 {
+		t.Fatalf("Unexpected room %+v", room)
+	}
+	var points int
+	if err := order.Extra.Decode("loyalty.points", &points); err != nil || points != 120 {
+		t.Errorf("Expected 120 points, got %v, %v", points, err)
+	}
+	if upgrade, _ := room.Extra.Get("upgrade"); string(upgrade) != `"suite"` || room.RoomRatePkg.Extra != nil {
+		t.Errorf("Expected the upgrade in the Extra of the room, got %v and %v", room.Extra, room.RoomRatePkg.Extra)
+	}
+
+	out, err := json.Marshal(&BookResp{HotelOrder: &order})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	for _, want := range []string{`"loyalty":{"points":120}`, `"upgrade":"suite"`, `"roomIndex":1`, `"status":2`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected %s in %s", want, out)
+		}
+	}
+}
+
+func TestHotelExtraEncoding(t *testing.T) {
+	// Without Extra, the types encode as encoding/json would
+	type plainRoomRatePkg RoomRatePkg
+	type plainHotel Hotel
+	pkg := RoomRatePkg{RatePkgId: "pkg-1", RateComment: "c"}
+	pkg.CheckIn = 20260101
+	hotel := Hotel{ID: 1, IsAvailable: true, Rooms: []Room{{RoomTypeId: "R1", Rates: []RoomRatePkg{pkg}}}}
+	for _, c := range []struct{ v, plain any }{
+		{pkg, plainRoomRatePkg(pkg)},
+		{hotel, plainHotel(hotel)},
+		{Hotel{}, plainHotel{}},
+	} {
+		got, err := json.Marshal(c.v)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		want, _ := json.Marshal(c.plain)
+		if string(got) != string(want) {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	}
+
+	var decoded Hotel
+	if err := json.Unmarshal([]byte(`{"id":"1","rooms":[{"rates":[{"ratePkgId":"p","tier":"gold"}]}],"score":9.5}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if tier, _ := decoded.Rooms[0].Rates[0].Extra.Get("tier"); string(tier) != `"gold"` {
+		t.Errorf("Expected the tier of the rate, got %v", decoded.Rooms[0].Rates[0].Extra)
+	}
+	if score, _ := decoded.Extra.Get("score"); string(score) != "9.5" {
+		t.Errorf("Expected the score of the hotel, got %v", decoded.Extra)
+	}
+}
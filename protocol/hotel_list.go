@@ -36,6 +36,7 @@ type Hotel struct {
 	MinPrice    types.Money `json:"minPrice,omitzero" example:"{\"amount\":100,\"currency\":\"USD\"}"` // the minPrice meeting search criteria
 	IsAvailable bool        `json:"isAvailable,omitempty" example:"true"`
 	Rooms       []Room      `json:"rooms,omitzero"`
+	Extra       types.Extra `json:"-"` // members the SDK has no field for yet, see types.Extra
 }
 type Room struct {
 	RoomTypeId   string        `json:"roomTypeId,omitempty" required:"true"`  // standardized roomTypeId, e.g. "R001"
@@ -54,6 +55,7 @@ type RoomRatePkg struct {
 	IncludesPackaging  bool               `json:"includesPackaging,omitempty"` // whether this is a packaged product (e.g., flight+hotel combo)
 	RatePlan
 	CheckInOut
+	Extra types.Extra `json:"-"` // members the SDK has no field for yet, see types.Extra
 }
 
 type ComputedCancelPolicy struct {
@@ -84,6 +86,11 @@ func (r RefundableMode) Bool() bool {
 	return r != RefundableModeNo
 }
 
+// AllRefundableModes returns all the RefundableMode enum values
+func AllRefundableModes() []RefundableMode {
+	return []RefundableMode{RefundableModeFully, RefundableModePartially, RefundableModeNo}
+}
+
 type RatePlan struct {
 	// Board information - Standard meal plan following liteapi standard
 	Board Board `json:"board,omitzero"` // Meal plan information (BoardId, BoardName, BoardDesc)
@@ -7,12 +7,12 @@ type GuestPerRoom struct {
 }
 
 type Guest struct {
-	RoomIndex       int64  `json:"roomIndex" required:"true"`                          // Assigned room index for this guest, starts from 1
-	FirstName       string `json:"firstName" required:"true" example:"John2"`          // First name of this guest
-	LastName        string `json:"lastName" required:"true" example:"Doe"`             // Last name of this guest
-	NationalityCode string `json:"nationalityCode" required:"false" example:"US"`      // Nationality code of this guest
-	Age             int64  `json:"age,omitempty" required:"false" example:"18"`        // Age of this guest, only matters for children
-	IsChild         bool   `json:"isChild,omitempty" required:"false" example:"false"` // Indicates if this guest is a child, determined by clients themselves
+	RoomIndex       int64  `json:"roomIndex" required:"true"`                                       // Assigned room index for this guest, starts from 1
+	FirstName       string `json:"firstName" required:"true" example:"John2" pii:"name"`            // First name of this guest
+	LastName        string `json:"lastName" required:"true" example:"Doe" pii:"name"`               // Last name of this guest
+	NationalityCode string `json:"nationalityCode" required:"false" example:"US" pii:"nationality"` // Nationality code of this guest
+	Age             int64  `json:"age,omitempty" required:"false" example:"18"`                     // Age of this guest, only matters for children
+	IsChild         bool   `json:"isChild,omitempty" required:"false" example:"false"`              // Indicates if this guest is a child, determined by clients themselves
 }
 
 // Occupancies contains guest and room configuration for hotel searches
@@ -85,6 +85,25 @@ const (
 	DestinationType_BusStation DestinationType = 12
 )
 
+// AllDestinationTypes returns all the DestinationType enum values
+func AllDestinationTypes() []DestinationType {
+	return []DestinationType{
+		DestinationType_Unknown,
+		DestinationType_Continent,
+		DestinationType_Country,
+		DestinationType_ProvinceState,
+		DestinationType_HighLevelRegion,
+		DestinationType_MultiCityVicinity,
+		DestinationType_City,
+		DestinationType_Neighborhood,
+		DestinationType_Airport,
+		DestinationType_PointOfInterest,
+		DestinationType_TrainStation,
+		DestinationType_MetroStation,
+		DestinationType_BusStation,
+	}
+}
+
 // String 为 Type 类型实现 String 方法，用于返回枚举值的字符串表示
 // String implements the String method for the Type type to return the string representation of the enum value
 func (t DestinationType) String() string {
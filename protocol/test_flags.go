@@ -0,0 +1,177 @@
+package protocol
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TestScenario is a scenario reproduced by the sandbox, see TestFlags.Scenario
+type TestScenario string
+
+// TestScenarioPriceChange is the scenario documented for the sandbox: the price of the hotel changes
+// between CheckAvail and Book. The hotelbytetest fake server reproduces more scenarios.
+const TestScenarioPriceChange TestScenario = "priceChange"
+
+// TestSimulator switches the supplier simulator of the sandbox
+type TestSimulator string
+
+const (
+	TestSimulatorEnable  TestSimulator = "ENABLE"
+	TestSimulatorDisable TestSimulator = "DISABLE" // query the real suppliers
+)
+
+// Keys of the test flags
+const (
+	TestFlagHotel                       = "hotel"
+	TestFlagScenario                    = "scenario"
+	TestFlagSimulator                   = "simulator"
+	TestFlagOnlyAvailableSupplierHotels = "onlyAvailableSupplierHotels"
+	// Parameters of the hotelbytetest fake server only
+	TestFlagRatio      = "ratio"
+	TestFlagDelay      = "delay"
+	TestFlagCount      = "count"
+	TestFlagStatus     = "status"
+	TestFlagRetryAfter = "retryAfter"
+)
+
+var testFlagKeys = map[string]bool{
+	TestFlagHotel:                       true,
+	TestFlagScenario:                    true,
+	TestFlagSimulator:                   true,
+	TestFlagOnlyAvailableSupplierHotels: true,
+	TestFlagRatio:                       true,
+	TestFlagDelay:                       true,
+	TestFlagCount:                       true,
+	TestFlagStatus:                      true,
+	TestFlagRetryAfter:                  true,
+}
+
+// TestFlags are the typed test flags of TestOption. Zero fields are not sent.
+//
+//	req.TestOption = protocol.TestFlags{Hotel: "HC1", Scenario: protocol.TestScenarioPriceChange}.Option()
+type TestFlags struct {
+	Hotel                       string        // restricts the scenario to the hotel code, e.g. "HC1"
+	Scenario                    TestScenario  // scenario to reproduce
+	Simulator                   TestSimulator // supplier simulator
+	OnlyAvailableSupplierHotels bool          // only return hotels available at the suppliers
+	// The parameters below are understood by the hotelbytetest fake server only
+	Ratio      float64       // price ratio of TestScenarioPriceChange
+	Delay      time.Duration // delay of the slow scenario
+	Count      int           // burst length of the rateLimited and serverError scenarios
+	Status     int           // 5xx status of the serverError scenario
+	RetryAfter int           // Retry-After of the rateLimited scenario, in seconds
+	// Extra holds flags unknown to this version of the SDK
+	Extra map[string]string
+}
+
+// ParseTestFlags parses test flags in the header format, e.g. "hotel=HC1&scenario=priceChange",
+// and validates them. Unknown keys are kept in Extra.
+func ParseTestFlags(s string) (TestFlags, error) {
+	var f TestFlags
+	values, err := url.ParseQuery(s)
+	if err != nil {
+		return f, fmt.Errorf("invalid test flags %q: %w", s, err)
+	}
+	for key := range values {
+		v := values.Get(key)
+		switch key {
+		case TestFlagHotel:
+			f.Hotel = v
+		case TestFlagScenario:
+			f.Scenario = TestScenario(v)
+		case TestFlagSimulator:
+			f.Simulator = TestSimulator(v)
+		case TestFlagOnlyAvailableSupplierHotels:
+			f.OnlyAvailableSupplierHotels, err = strconv.ParseBool(v)
+		case TestFlagRatio:
+			f.Ratio, err = strconv.ParseFloat(v, 64)
+		case TestFlagDelay:
+			f.Delay, err = time.ParseDuration(v)
+		case TestFlagCount:
+			f.Count, err = strconv.Atoi(v)
+		case TestFlagStatus:
+			f.Status, err = strconv.Atoi(v)
+		case TestFlagRetryAfter:
+			f.RetryAfter, err = strconv.Atoi(v)
+		default:
+			if f.Extra == nil {
+				f.Extra = make(map[string]string)
+			}
+			f.Extra[key] = v
+		}
+		if err != nil {
+			return TestFlags{}, fmt.Errorf("invalid test flag %s=%q", key, v)
+		}
+	}
+	if err := f.Validate(); err != nil {
+		return TestFlags{}, err
+	}
+	return f, nil
+}
+
+// Validate checks the numeric values of the flags. Scenarios and simulator values unknown to this
+// version of the SDK are kept, the server ignoring those it does not know either.
+func (f TestFlags) Validate() error {
+	if f.Ratio < 0 {
+		return fmt.Errorf("test ratio must >= 0")
+	}
+	if f.Delay < 0 {
+		return fmt.Errorf("test delay must >= 0")
+	}
+	if f.Count < 0 {
+		return fmt.Errorf("test count must >= 0")
+	}
+	if f.Status != 0 && (f.Status < 500 || f.Status > 599) {
+		return fmt.Errorf("test status must be 5xx")
+	}
+	if f.RetryAfter < 0 {
+		return fmt.Errorf("test retryAfter must >= 0")
+	}
+	for key := range f.Extra {
+		if key == "" || testFlagKeys[key] {
+			return fmt.Errorf("invalid extra test flag %q", key)
+		}
+	}
+	return nil
+}
+
+// IsZero reports whether no flag is set
+func (f TestFlags) IsZero() bool {
+	return f.Hotel == "" && f.Scenario == "" && f.Simulator == "" && !f.OnlyAvailableSupplierHotels &&
+		f.Ratio == 0 && f.Delay == 0 && f.Count == 0 && f.Status == 0 && f.RetryAfter == 0 && len(f.Extra) == 0
+}
+
+// String encodes the flags in the header format, with keys sorted
+func (f TestFlags) String() string {
+	values := make(url.Values)
+	for k, v := range f.Extra {
+		values.Set(k, v)
+	}
+	set := func(key, value string, ok bool) {
+		if ok {
+			values.Set(key, value)
+		}
+	}
+	set(TestFlagHotel, f.Hotel, f.Hotel != "")
+	set(TestFlagScenario, string(f.Scenario), f.Scenario != "")
+	set(TestFlagSimulator, string(f.Simulator), f.Simulator != "")
+	set(TestFlagOnlyAvailableSupplierHotels, "true", f.OnlyAvailableSupplierHotels)
+	set(TestFlagRatio, strconv.FormatFloat(f.Ratio, 'f', -1, 64), f.Ratio != 0)
+	set(TestFlagDelay, f.Delay.String(), f.Delay != 0)
+	set(TestFlagCount, strconv.Itoa(f.Count), f.Count != 0)
+	set(TestFlagStatus, strconv.Itoa(f.Status), f.Status != 0)
+	set(TestFlagRetryAfter, strconv.Itoa(f.RetryAfter), f.RetryAfter != 0)
+	return values.Encode()
+}
+
+// Option returns the TestOption carrying the flags
+func (f TestFlags) Option() TestOption {
+	return TestOption{Test: f.String()}
+}
+
+// Flags parses the test flags of the option
+func (o TestOption) Flags() (TestFlags, error) {
+	return ParseTestFlags(o.Test)
+}
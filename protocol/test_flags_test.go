@@ -0,0 +1,56 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTestFlagsRoundTrip(t *testing.T) {
+	flags := TestFlags{
+		Hotel:      "461850557",
+		Scenario:   "slow",
+		Delay:      1500 * time.Millisecond,
+		Count:      2,
+		Extra:      map[string]string{"supplier": "S1"},
+		Simulator:  TestSimulatorDisable,
+		RetryAfter: 3,
+	}
+	encoded := flags.String()
+	if encoded != "count=2&delay=1.5s&hotel=461850557&retryAfter=3&scenario=slow&simulator=DISABLE&supplier=S1" {
+		t.Errorf("Unexpected encoding %q", encoded)
+	}
+	parsed, err := ParseTestFlags(encoded)
+	if err != nil {
+		t.Fatalf("ParseTestFlags failed: %v", err)
+	}
+	if parsed.String() != encoded || parsed.Extra["supplier"] != "S1" || parsed.Delay != flags.Delay {
+		t.Errorf("Expected %+v, got %+v", flags, parsed)
+	}
+
+	if flags, err := (TestOption{Test: "simulator=DISABLE&onlyAvailableSupplierHotels=true"}).Flags(); err != nil || !flags.OnlyAvailableSupplierHotels {
+		t.Errorf("Unexpected flags %+v, %v", flags, err)
+	}
+	if flags, err := ParseTestFlags(""); err != nil || !flags.IsZero() || flags.Option().Test != "" {
+		t.Errorf("Expected empty flags, got %+v, %v", flags, err)
+	}
+}
+
+func TestTestFlagsValidate(t *testing.T) {
+	for _, test := range []string{
+		"ratio=-1",
+		"delay=soon",
+		"count=many",
+		"status=404",
+		"onlyAvailableSupplierHotels=maybe",
+	} {
+		if _, err := ParseTestFlags(test); err == nil {
+			t.Errorf("Expected %q to be rejected", test)
+		}
+	}
+	if flags, err := ParseTestFlags("scenario=newScenario&simulator=OFF"); err != nil || flags.Scenario != "newScenario" {
+		t.Errorf("Expected unknown values to be kept, got %+v, %v", flags, err)
+	}
+	if err := (TestFlags{Extra: map[string]string{TestFlagHotel: "1"}}).Validate(); err == nil {
+		t.Error("Expected Extra shadowing a known flag to be rejected")
+	}
+}
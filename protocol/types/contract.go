@@ -0,0 +1,242 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/hotelbyte-com/sdk-go/internal/jsonfields"
+	"github.com/hotelbyte-com/sdk-go/internal/wire"
+)
+
+// ContractMode tells how NewResponse checks a payload against the type it decodes to
+type ContractMode int
+
+const (
+	ContractOff    ContractMode = iota
+	ContractWarn                // violations are passed to ContractCheck.Report, and the payload decoded
+	ContractStrict              // violations fail the decoding with a *ContractError
+)
+
+// ContractCheck enables the checks of a response payload in NewResponse, to catch API drift such as
+// renamed fields or changed shapes before they reach production
+type ContractCheck struct {
+	Mode ContractMode
+	// Report receives the violations in ContractWarn mode
+	Report func(violations []ContractViolation)
+}
+
+// ViolationKind is a kind of contract violation
+type ViolationKind string
+
+const (
+	ViolationUnknownField    ViolationKind = "unknown_field"    // a member the type has no field for
+	ViolationTypeMismatch    ViolationKind = "type_mismatch"    // e.g. a string where an array is expected
+	ViolationMissingRequired ViolationKind = "missing_required" // a field tagged `required:"true"` absent or null
+)
+
+// ContractViolation is a difference between a payload and the type it decodes to
+type ContractViolation struct {
+	Kind    ViolationKind
+	Path    string // JSON path of the member, e.g. "data.list[0].id"
+	Message string
+}
+
+func (v ContractViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Message)
+}
+
+// ContractError is returned by NewResponse in ContractStrict mode when the payload violates the contract
+type ContractError struct {
+	Violations []ContractViolation
+}
+
+func (e *ContractError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.String()
+	}
+	return "response contract violated: " + strings.Join(msgs, "; ")
+}
+
+// CheckContract returns the violations of a JSON payload against the type T it decodes to: members
+// T has no field for, members of another JSON type than their field decodes from, and fields tagged
+// `required:"true"` that are absent or null. The rules are those of the JSON Schemas of the openapi
+// package.
+func CheckContract[T any](body []byte) ([]ContractViolation, error) {
+	var payload any
+	if err := sonic.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid body %w", err)
+	}
+	var violations []ContractViolation
+	checkValue(payload, reflect.TypeOf((*T)(nil)).Elem(), "", &violations)
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+	return violations, nil
+}
+
+// check runs the checks of the mode: an error in ContractStrict mode, a report in ContractWarn mode
+func (c *ContractCheck) check(checkContract func([]byte) ([]ContractViolation, error), body []byte) error {
+	violations, err := checkContract(body)
+	if err != nil || len(violations) == 0 {
+		// Invalid bodies are reported by the decoding
+		return nil
+	}
+	if c.Mode == ContractStrict {
+		return &ContractError{Violations: violations}
+	}
+	if c.Report != nil {
+		c.Report(violations)
+	}
+	return nil
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+func checkValue(v any, t reflect.Type, path string, violations *[]ContractViolation) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if v == nil {
+		return
+	}
+	mismatch := func(want string) {
+		*violations = append(*violations, ContractViolation{
+			Kind:    ViolationTypeMismatch,
+			Path:    path,
+			Message: fmt.Sprintf("expected %s, got %s", want, jsonTypeOf(v)),
+		})
+	}
+	if scalar, ok := wire.ScalarOf(t); ok {
+		want := scalar.JSONTypes
+		if len(want) > 0 && !slices.Contains(want, jsonTypeOf(v)) {
+			mismatch(strings.Join(want, " or "))
+			return
+		}
+		if _, ok := v.([]any); !ok || t.Kind() != reflect.Slice {
+			return
+		}
+	}
+	if t.Kind() != reflect.Slice && reflect.PointerTo(t).Implements(unmarshalerType) &&
+		!(t.Kind() == reflect.Struct && holdsExtra(t)) {
+		// A format of its own, e.g. a date; IDs are checked as the array they are, and the types
+		// holding an Extra as the objects they are
+		return
+	}
+	switch t.Kind() {
+	case reflect.String:
+		if _, ok := v.(string); !ok {
+			mismatch("string")
+		}
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			mismatch("boolean")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if _, ok := v.(float64); !ok {
+			mismatch("number")
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			if _, ok := v.(string); !ok {
+				mismatch("string")
+			}
+			return
+		}
+		items, ok := v.([]any)
+		if !ok {
+			mismatch("array")
+			return
+		}
+		for i, item := range items {
+			checkValue(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), violations)
+		}
+	case reflect.Map:
+		members, ok := v.(map[string]any)
+		if !ok {
+			mismatch("object")
+			return
+		}
+		for key, member := range members {
+			checkValue(member, t.Elem(), joinPath(path, key), violations)
+		}
+	case reflect.Struct:
+		members, ok := v.(map[string]any)
+		if !ok {
+			mismatch("object")
+			return
+		}
+		checkObject(members, t, path, violations)
+	}
+}
+
+func checkObject(members map[string]any, t reflect.Type, path string, violations *[]ContractViolation) {
+	fields := jsonfields.Of(t)
+	for key, member := range members {
+		f, ok := jsonfields.Lookup(fields, key)
+		if !ok {
+			*violations = append(*violations, ContractViolation{
+				Kind:    ViolationUnknownField,
+				Path:    joinPath(path, key),
+				Message: "unknown field",
+			})
+			continue
+		}
+		checkValue(member, f.Type, joinPath(path, key), violations)
+	}
+	for _, f := range fields {
+		if !wire.Required(f.StructField) {
+			continue
+		}
+		if v := memberOf(members, f.Name); v == nil {
+			*violations = append(*violations, ContractViolation{
+				Kind:    ViolationMissingRequired,
+				Path:    joinPath(path, f.Name),
+				Message: "missing required field",
+			})
+		}
+	}
+}
+
+// memberOf returns the member decoding to the field name, nil if none
+func memberOf(members map[string]any, name string) any {
+	if v, ok := members[name]; ok {
+		return v
+	}
+	for key, v := range members {
+		if strings.EqualFold(key, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+func jsonTypeOf(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
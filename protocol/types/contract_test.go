@@ -0,0 +1,96 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type contractItem struct {
+	Id   ID      `json:"id" required:"true"`
+	Name string  `json:"name"`
+	Date DateInt `json:"date"`
+}
+
+type contractRoom struct {
+	RoomTypeId string `json:"roomTypeId,omitempty" required:"true"`
+}
+
+type contractData struct {
+	contractItem
+	Ids   IDs             `json:"ids"`
+	Items []*contractItem `json:"items"`
+	Tags  map[string]int  `json:"tags"`
+}
+
+func TestCheckContract(t *testing.T) {
+	body := []byte(`{"code":0,"data":{"id":1,"name":"n","date":"2026-01-01","ids":true,"rating":4,` +
+		`"items":[{"id":"2","name":3},{"name":"m","date":null}],"tags":{"a":"b"}}}`)
+	violations, err := CheckContract[Response[contractData]](body)
+	if err != nil {
+		t.Fatalf("CheckContract failed: %v", err)
+	}
+	var got []string
+	for _, v := range violations {
+		got = append(got, string(v.Kind)+" "+v.String())
+	}
+	want := []string{
+		"type_mismatch data.ids: expected array or string or number, got boolean",
+		"type_mismatch data.items[0].name: expected string, got number",
+		"missing_required data.items[1].id: missing required field",
+		"unknown_field data.rating: unknown field",
+		"type_mismatch data.tags.a: expected number, got string",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected violations\n%q\ngot\n%q", want, got)
+	}
+
+	if violations, _ := CheckContract[Response[contractData]]([]byte(`{"code":0,"data":{"id":"1","date":20260101,"ids":"1,2","items":[{"id":2}]}}`)); len(violations) != 0 {
+		t.Errorf("Expected no violations, got %v", violations)
+	}
+	// Required whether or not the zero value is omitted, as in the JSON Schemas
+	if violations, _ := CheckContract[contractRoom]([]byte(`{}`)); len(violations) != 1 || violations[0].Kind != ViolationMissingRequired {
+		t.Errorf("Expected roomTypeId to be reported, got %v", violations)
+	}
+	// The types keeping unknown members in an Extra are checked all the same
+	if violations, _ := CheckContract[extraHolder]([]byte(`{"currency":"USD","amount":1,"flag":true}`)); len(violations) != 1 || violations[0].Path != "flag" {
+		t.Errorf("Expected flag to be reported, got %v", violations)
+	}
+	if _, err := CheckContract[Response[contractData]]([]byte(`{`)); err == nil {
+		t.Error("Expected an error for an invalid body")
+	}
+}
+
+func TestNewResponseContract(t *testing.T) {
+	body := []byte(`{"code":0,"data":{"id":1,"extra":true}}`)
+
+	if _, err := NewResponse[contractData](&HttpResponse{Body: body, Contract: &ContractCheck{Mode: ContractOff}}); err != nil {
+		t.Fatalf("Expected no checks when off, got %v", err)
+	}
+
+	var reported []ContractViolation
+	warn := &ContractCheck{Mode: ContractWarn, Report: func(v []ContractViolation) { reported = v }}
+	resp, err := NewResponse[contractData](&HttpResponse{Body: body, Contract: warn})
+	if err != nil || resp.Data.Id != 1 {
+		t.Fatalf("Expected the data in warn mode, got %v, %v", resp, err)
+	}
+	if len(reported) != 1 || reported[0].Path != "data.extra" {
+		t.Errorf("Expected data.extra reported, got %v", reported)
+	}
+
+	_, err = NewResponse[contractData](&HttpResponse{Body: body, Contract: &ContractCheck{Mode: ContractStrict}})
+	var contractErr *ContractError
+	if !errors.As(err, &contractErr) || len(contractErr.Violations) != 1 {
+		t.Fatalf("Expected a *ContractError, got %v", err)
+	}
+	if err.Error() != "response contract violated: data.extra: unknown field" {
+		t.Errorf("Unexpected error %q", err)
+	}
+
+	// Business errors come first
+	_, err = NewResponse[contractData](&HttpResponse{Body: []byte(`{"code":1001,"msg":"no","x":1}`), Contract: &ContractCheck{Mode: ContractStrict}})
+	var bizErr *BizError
+	if !errors.As(err, &bizErr) {
+		t.Errorf("Expected a *BizError, got %v", err)
+	}
+}
@@ -46,21 +46,19 @@ func (e *BizError) StatusMessage() string {
 	return e.Msg
 }
 
-// Is implement errors.Is
+// Is implement errors.Is: BizErrors with the same code match
 func (e *BizError) Is(oe error) bool {
-	return e.HasSameCode(oe)
+	target, ok := oe.(*BizError)
+	return ok && e != nil && target != nil && e.Code == target.Code
 }
 
-func (e *BizError) HasSameCode(err error) (ok bool) {
-	if errors.Is(err, e) {
-		return true
-	}
-	var bizErr *BizError
-
-	if bizErr, ok = UnwrapForBizErr(err); ok {
-		return e.Code == bizErr.Code
+// HasSameCode reports whether err wraps a BizError with the code of e
+func (e *BizError) HasSameCode(err error) bool {
+	if e == nil {
+		return false
 	}
-	return false
+	bizErr, ok := UnwrapForBizErr(err)
+	return ok && e.Code == bizErr.Code
 }
 
 func NewBizErr(statusCode int32, msg string) *BizError {
@@ -0,0 +1,20 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestBizErrorIs(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", NewBizErr(3002, "price changed"))
+	if !errors.Is(err, NewBizErr(3002, "other message")) {
+		t.Error("Expected BizErrors with the same code to match")
+	}
+	if errors.Is(err, NewBizErr(3001, "price changed")) {
+		t.Error("Expected BizErrors with different codes not to match")
+	}
+	if !NewBizErr(3002, "").HasSameCode(err) || NewBizErr(3001, "").HasSameCode(err) {
+		t.Error("Unexpected HasSameCode result")
+	}
+}
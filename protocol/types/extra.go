@@ -0,0 +1,240 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/hotelbyte-com/sdk-go/internal/jsonfields"
+)
+
+// Extra holds the JSON members a type has no field for, e.g. fields added to the API after the SDK
+// release, so that they survive a decoding and an encoding
+type Extra map[string]json.RawMessage
+
+var extraType = reflect.TypeOf(Extra(nil))
+
+// Get returns the value at a path of member names and array indexes, e.g. "policy.fees[0].amount"
+func (e Extra) Get(path string) (json.RawMessage, bool) {
+	steps, err := parsePath(path)
+	if err != nil || len(steps) == 0 || steps[0].index >= 0 {
+		return nil, false
+	}
+	value, ok := e[steps[0].key]
+	for _, s := range steps[1:] {
+		if !ok {
+			break
+		}
+		if s.index >= 0 {
+			var items []json.RawMessage
+			if sonic.Unmarshal(value, &items) != nil || s.index >= len(items) {
+				return nil, false
+			}
+			value = items[s.index]
+			continue
+		}
+		var members map[string]json.RawMessage
+		if sonic.Unmarshal(value, &members) != nil {
+			return nil, false
+		}
+		value, ok = members[s.key]
+	}
+	return value, ok
+}
+
+// Decode decodes the value at a path, see Get, into v
+func (e Extra) Decode(path string, v any) error {
+	value, ok := e.Get(path)
+	if !ok {
+		return fmt.Errorf("no extra value at %s", path)
+	}
+	return sonic.Unmarshal(value, v)
+}
+
+// pathStep is a member name, or an array index if index >= 0
+type pathStep struct {
+	key   string
+	index int
+}
+
+func parsePath(path string) ([]pathStep, error) {
+	var steps []pathStep
+	for _, part := range strings.Split(path, ".") {
+		key, indexes, _ := strings.Cut(part, "[")
+		if key != "" {
+			steps = append(steps, pathStep{key: key, index: -1})
+		}
+		if indexes == "" {
+			continue
+		}
+		for _, index := range strings.Split(strings.TrimSuffix(indexes, "]"), "][") {
+			i, err := strconv.Atoi(index)
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("invalid index %q of %s", index, path)
+			}
+			steps = append(steps, pathStep{index: i})
+		}
+	}
+	return steps, nil
+}
+
+// UnmarshalExtra decodes a JSON object into v, a pointer to a struct, member by member, and returns
+// the members v has no field for. Types holding an Extra decode through it:
+//
+//	func (h *Hotel) UnmarshalJSON(data []byte) (err error) {
+//		h.Extra, err = types.UnmarshalExtra(data, h)
+//		return err
+//	}
+//
+// The fields of embedded structs are decoded as encoding/json does, without the UnmarshalJSON
+// methods they may promote.
+func UnmarshalExtra(data []byte, v any) (Extra, error) {
+	var members map[string]json.RawMessage
+	if err := sonic.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	rv := reflect.ValueOf(v).Elem()
+	fields := jsonfields.Of(rv.Type())
+	var extra Extra
+	for key, value := range members {
+		f, ok := jsonfields.Lookup(fields, key)
+		if !ok {
+			if extra == nil {
+				extra = make(Extra)
+			}
+			// A copy, the decoder may share the buffer of data
+			extra[key] = append(json.RawMessage(nil), value...)
+			continue
+		}
+		fv, err := fieldByIndex(rv, f.Index, true)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if err := sonic.Unmarshal(value, fv.Addr().Interface()); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return extra, nil
+}
+
+// MarshalExtra encodes v, a pointer to a struct, field by field as encoding/json does, then the
+// members of extra it has no field for, in key order; see UnmarshalExtra
+func MarshalExtra(v any, extra Extra) ([]byte, error) {
+	rv := reflect.ValueOf(v).Elem()
+	fields := jsonfields.Of(rv.Type())
+	var b bytes.Buffer
+	b.WriteByte('{')
+	member := func(key string, value []byte) error {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		name, err := sonic.Marshal(key)
+		if err != nil {
+			return err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+		return nil
+	}
+	for _, f := range fields {
+		fv, err := fieldByIndex(rv, f.Index, false)
+		if err != nil || omitted(f, fv) {
+			// Behind a nil embedded pointer
+			continue
+		}
+		value, err := sonic.Marshal(fv.Addr().Interface())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if err := member(f.Name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		if _, ok := jsonfields.Lookup(fields, key); !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := extra[key]
+		if len(value) == 0 {
+			value = json.RawMessage("null")
+		}
+		if err := member(key, value); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// fieldByIndex returns the field at index, allocating the nil embedded pointers on the way if alloc,
+// else failing on them
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("nil embedded pointer to %s", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// omitted reports whether a field is left out by its omitempty or omitzero option
+func omitted(f jsonfields.Field, v reflect.Value) bool {
+	_, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+	for _, opt := range strings.Split(opts, ",") {
+		switch opt {
+		case "omitempty":
+			if isEmpty(v) {
+				return true
+			}
+		case "omitzero":
+			if z, ok := v.Addr().Interface().(interface{ IsZero() bool }); ok {
+				if z.IsZero() {
+					return true
+				}
+			} else if v.IsZero() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isEmpty reports whether a value is empty as omitempty tells
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// holdsExtra reports whether a struct has an Extra field, so that it is an object however it decodes
+func holdsExtra(t reflect.Type) bool {
+	f, ok := t.FieldByName("Extra")
+	return ok && f.Type == extraType
+}
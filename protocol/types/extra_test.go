@@ -0,0 +1,86 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type extraHolder struct {
+	*Money
+	Id    ID      `json:"id"`
+	Name  string  `json:"name,omitempty"`
+	Date  DateInt `json:"date,omitzero"`
+	Ids   IDs     `json:"ids"`
+	Extra Extra   `json:"-"`
+}
+
+func (h *extraHolder) UnmarshalJSON(data []byte) (err error) {
+	h.Extra, err = UnmarshalExtra(data, h)
+	return err
+}
+
+func (h extraHolder) MarshalJSON() ([]byte, error) {
+	return MarshalExtra(&h, h.Extra)
+}
+
+func TestExtraRoundTrip(t *testing.T) {
+	var h extraHolder
+	body := `{"id":"7","currency":"USD","NAME":"n","ids":"1,2","policy":{"fees":[{"amount":10},{"amount":20}]},"flag":true}`
+	if err := json.Unmarshal([]byte(body), &h); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if h.Money == nil || h.Currency != "USD" || h.Id != 7 || h.Name != "n" || len(h.Ids) != 2 {
+		t.Fatalf("Unexpected fields %+v", h)
+	}
+	if len(h.Extra) != 2 || string(h.Extra["flag"]) != "true" {
+		t.Fatalf("Expected policy and flag in Extra, got %v", h.Extra)
+	}
+
+	out, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"currency":"USD","amount":0,"id":"7","name":"n","ids":["1","2"],"flag":true,"policy":{"fees":[{"amount":10},{"amount":20}]}}`
+	if string(out) != want {
+		t.Errorf("Expected %s, got %s", want, out)
+	}
+
+	// Fields behind a nil embedded pointer and omitted ones are left out, and Extra never shadows a field
+	out, err = json.Marshal(extraHolder{Extra: Extra{"name": json.RawMessage(`"x"`), "y": nil}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if want := `{"id":"","ids":null,"y":null}`; string(out) != want {
+		t.Errorf("Expected %s, got %s", want, out)
+	}
+}
+
+func TestExtraGet(t *testing.T) {
+	extra := Extra{
+		"policy": json.RawMessage(`{"fees":[{"amount":10},{"amount":20}],"matrix":[[1,2],[3,4]]}`),
+		"flag":   json.RawMessage(`true`),
+	}
+	for path, want := range map[string]string{
+		"flag":                  "true",
+		"policy.fees[1].amount": "20",
+		"policy.fees[0]":        `{"amount":10}`,
+		"policy.matrix[1][0]":   "3",
+	} {
+		if v, ok := extra.Get(path); !ok || string(v) != want {
+			t.Errorf("Expected %s at %s, got %s, %v", want, path, v, ok)
+		}
+	}
+	for _, path := range []string{"", "missing", "flag.x", "policy.fees[2]", "policy.fees[x]", "[0]", "policy.none.amount"} {
+		if v, ok := extra.Get(path); ok {
+			t.Errorf("Expected nothing at %q, got %s", path, v)
+		}
+	}
+
+	var amount float64
+	if err := extra.Decode("policy.fees[1].amount", &amount); err != nil || amount != 20 {
+		t.Errorf("Expected 20, got %v, %v", amount, err)
+	}
+	if err := extra.Decode("missing", &amount); err == nil {
+		t.Error("Expected an error for a missing path")
+	}
+}
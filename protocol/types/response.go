@@ -2,10 +2,13 @@ package types
 
 import (
 	"fmt"
-	"github.com/bytedance/sonic"
 	"net/http"
 	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
 )
 
 type Response[T any] struct {
@@ -20,7 +23,7 @@ func NewResponseData[T any](r *HttpResponse) (*T, error) {
 		return nil, err
 	}
 
-	// 如果 T 类型中有 header 字段，则将 v.Header赋值给 T
+	// 将响应头填充到 T 中带 api.header 标签的字段
 	if v.Data != nil {
 		setHeaderField(v.Data, v.Header)
 	}
@@ -28,35 +31,82 @@ func NewResponseData[T any](r *HttpResponse) (*T, error) {
 	return v.Data, nil
 }
 
-// 使用反射为结构体设置header字段
+// setHeaderField fills data from the response headers: fields tagged `api.header:"Name"` get the
+// named header, e.g. CommonHeader.TraceId, and http.Header fields named Header get all of them.
+// Nested and embedded structs are walked; slices and maps are not.
 func setHeaderField[T any](data *T, header http.Header) {
-	if data == nil {
+	if data == nil || len(header) == 0 {
 		return
 	}
+	fillHeaderFields(reflect.ValueOf(data).Elem(), canonicalHeader(header), 0)
+}
 
-	// 获取值的反射对象
-	val := reflect.ValueOf(data).Elem()
-	typ := val.Type()
+// canonicalHeader returns header with canonical keys, so that Get finds headers set by hand too
+func canonicalHeader(header http.Header) http.Header {
+	canonical := make(http.Header, len(header))
+	for k, v := range header {
+		key := http.CanonicalHeaderKey(k)
+		canonical[key] = append(canonical[key], v...)
+	}
+	return canonical
+}
 
-	// 遍历结构体字段
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		fieldName := field.Name
+// maxHeaderDepth stops the walk of self-referencing types
+const maxHeaderDepth = 8
 
-		// 检查字段名是否为 "header"
-		if fieldName == "header" {
-			// 检查字段类型是否是 http.Header (map[string][]string)
-			if field.Type.Kind() == reflect.Map &&
-			   field.Type.Key().Kind() == reflect.String &&
-			   field.Type.Elem().Kind() == reflect.Slice &&
-			   field.Type.Elem().Elem().Kind() == reflect.String {
+var httpHeaderType = reflect.TypeOf(http.Header{})
 
-				headerValue := val.Field(i)
-				if headerValue.IsValid() && headerValue.CanSet() {
-					headerValue.Set(reflect.ValueOf(header))
-				}
-				break
+func fillHeaderFields(val reflect.Value, header http.Header, depth int) {
+	if val.Kind() != reflect.Struct || depth > maxHeaderDepth {
+		return
+	}
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fv := val.Field(i)
+		// Exported fields of embedded unexported structs are settable, as in encoding/json
+		if !fv.CanSet() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+		if name := field.Tag.Get("api.header"); name != "" {
+			if v := header.Get(name); v != "" {
+				setHeaderValue(fv, v)
+			}
+			continue
+		}
+		switch {
+		case field.Type == httpHeaderType:
+			if strings.EqualFold(field.Name, "header") {
+				fv.Set(reflect.ValueOf(header))
 			}
+		case field.Type.Kind() == reflect.Struct:
+			fillHeaderFields(fv, header, depth+1)
+		case field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct && !fv.IsNil():
+			fillHeaderFields(fv.Elem(), header, depth+1)
+		}
+	}
+}
+
+// setHeaderValue sets a string, numeric or boolean field from a header value; invalid values are ignored
+func setHeaderValue(fv reflect.Value, v string) {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(v, 10, fv.Type().Bits()); err == nil {
+			fv.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(v, 10, fv.Type().Bits()); err == nil {
+			fv.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(v, fv.Type().Bits()); err == nil {
+			fv.SetFloat(n)
+		}
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(v); err == nil {
+			fv.SetBool(b)
 		}
 	}
 }
@@ -73,6 +123,11 @@ func NewResponse[T any](r *HttpResponse) (*Response[T], error) {
 	if response.Code != 0 {
 		return nil, &response.BizError
 	}
+	if r.Contract != nil && r.Contract.Mode != ContractOff {
+		if err := r.Contract.check(CheckContract[Response[T]], r.Body); err != nil {
+			return nil, err
+		}
+	}
 	response.Header = r.Headers
 	return &response, nil
 }
@@ -84,6 +139,9 @@ type HttpRequest struct {
 	Query   url.Values
 	Headers map[string]string
 	Body    interface{}
+	// ResponseType is a nil pointer of the type of the response body, e.g.
+	// (*Response[protocol.BookResp])(nil), so that its PII fields are redacted in logs
+	ResponseType any
 }
 
 // HttpResponse represents HTTP response
@@ -91,4 +149,6 @@ type HttpResponse struct {
 	StatusCode int
 	Headers    http.Header
 	Body       []byte
+	// Contract checks the payload in NewResponse; nil skips the checks
+	Contract *ContractCheck
 }
@@ -7,8 +7,8 @@ import (
 
 // 定义一个包含header字段的结构体来测试
 type TestResponseWithHeader struct {
-	ID     int    `json:"id"`
-	Name   string `json:"name"`
+	ID     int         `json:"id"`
+	Name   string      `json:"name"`
 	Header http.Header `json:"header"`
 }
 
@@ -23,7 +23,7 @@ func TestNewResponseDataWithHeaderField(t *testing.T) {
 	responseBody := `{"code":0,"data":{"id":123,"name":"test","header":null}}`
 	response := &HttpResponse{
 		StatusCode: 200,
-		Headers:    http.Header{
+		Headers: http.Header{
 			"X-Custom-Header": []string{"value1"},
 			"X-Request-ID":    []string{"req-123"},
 		},
@@ -60,7 +60,7 @@ func TestNewResponseDataWithoutHeaderField(t *testing.T) {
 	responseBody := `{"code":0,"data":{"id":456,"name":"test-no-header"}}`
 	response := &HttpResponse{
 		StatusCode: 200,
-		Headers:    http.Header{
+		Headers: http.Header{
 			"X-Custom-Header": []string{"value2"},
 		},
 		Body: []byte(responseBody),
@@ -120,7 +120,7 @@ func TestNewResponseDataWithEmptyBody(t *testing.T) {
 func TestSetHeaderField(t *testing.T) {
 	// 测试设置header字段的辅助函数
 	header := http.Header{
-		"Content-Type": []string{"application/json"},
+		"Content-Type":  []string{"application/json"},
 		"Authorization": []string{"Bearer token"},
 	}
 
@@ -136,4 +136,48 @@ func TestSetHeaderField(t *testing.T) {
 	if data.Header.Get("Authorization") != "Bearer token" {
 		t.Errorf("Expected Authorization to be 'Bearer token', got '%s'", data.Header.Get("Authorization"))
 	}
-}
\ No newline at end of file
+}
+
+type testCommonHeader struct {
+	RequestId string `json:"requestId,omitempty" api.header:"Request-Id"`
+	TraceId   string `json:"traceId,omitempty" api.header:"Trace-Id"`
+}
+
+type testSession struct {
+	SessionId string `json:"sessionId,omitempty" api.header:"Session-Id"`
+}
+
+type TestResponseWithTaggedHeader struct {
+	testSession
+	ID       int               `json:"id"`
+	Header   testCommonHeader  `json:"header"`
+	Cost     int64             `json:"cost" api.header:"Server-Cost-Milliseconds"`
+	Previous *testCommonHeader `json:"previous"`
+}
+
+func TestNewResponseDataWithTaggedHeader(t *testing.T) {
+	response := &HttpResponse{
+		StatusCode: 200,
+		Headers: http.Header{
+			"Request-Id":               []string{"req-1"},
+			"trace-id":                 []string{"trace-1"},
+			"Session-Id":               []string{"session-1"},
+			"Server-Cost-Milliseconds": []string{"42"},
+		},
+		Body: []byte(`{"code":0,"data":{"id":1,"header":{"requestId":"from-body"}}}`),
+	}
+
+	result, err := NewResponseData[TestResponseWithTaggedHeader](response)
+	if err != nil {
+		t.Fatalf("NewResponseData failed: %v", err)
+	}
+	if result.Header.RequestId != "req-1" || result.Header.TraceId != "trace-1" {
+		t.Errorf("Expected the header struct to be filled, got %+v", result.Header)
+	}
+	if result.SessionId != "session-1" || result.Cost != 42 {
+		t.Errorf("Expected embedded and numeric fields to be filled, got %q and %d", result.SessionId, result.Cost)
+	}
+	if result.Previous != nil {
+		t.Errorf("Expected nil pointers to be left alone, got %+v", result.Previous)
+	}
+}
@@ -0,0 +1,263 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hotelbyte-com/sdk-go/internal/tags"
+)
+
+// FieldError is a field of a request failing validation
+type FieldError struct {
+	Field   string // JSON path of the field, e.g. "roomOccupancies[0].adultCount"
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationError lists the fields of a request failing validation
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks a request: the fields tagged `required:"true"` must be set, and the stay,
+// occupancies and guests must be consistent. It returns a *ValidationError listing every invalid field.
+func Validate(req any) error {
+	v := reflect.ValueOf(req)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	errs := &fieldErrors{}
+	validateValue(v, "", errs)
+	if len(errs.list) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: errs.list}
+}
+
+// ApplyDefaults sets the zero fields of a request to their `default:"..."` tag, or to the default
+// option of their json tag, e.g. `json:"pageSize,default=10"`. Dates accept the builtin functions of
+// types.DateInt, e.g. "now()+7".
+func ApplyDefaults(req any) error {
+	v := reflect.ValueOf(req)
+	if v.Kind() != reflect.Pointer {
+		return fmt.Errorf("defaults need a pointer, got %T", req)
+	}
+	if v.IsNil() {
+		return nil
+	}
+	return applyDefaults(v.Elem(), "")
+}
+
+// fieldErrors collects the invalid fields of a request
+type fieldErrors struct {
+	list []FieldError
+}
+
+// add reports a field, unless it is reported already, e.g. as required
+func (e *fieldErrors) add(field, format string, args ...any) {
+	for _, f := range e.list {
+		if f.Field == field {
+			return
+		}
+	}
+	e.list = append(e.list, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// rules are the checks of the types beyond their required tags, run with the path of the value
+var rules = map[reflect.Type]func(v reflect.Value, path string, errs *fieldErrors){
+	reflect.TypeOf(CheckInOut{}): func(v reflect.Value, path string, errs *fieldErrors) { v.Interface().(CheckInOut).validate(path, errs) },
+	reflect.TypeOf(Occupancies{}): func(v reflect.Value, path string, errs *fieldErrors) {
+		v.Interface().(Occupancies).validate(path, errs)
+	},
+	reflect.TypeOf(GuestPerRoom{}): func(v reflect.Value, path string, errs *fieldErrors) {
+		v.Interface().(GuestPerRoom).validate(path, errs)
+	},
+	reflect.TypeOf(Guest{}): func(v reflect.Value, path string, errs *fieldErrors) { v.Interface().(Guest).validate(path, errs) },
+}
+
+func (c CheckInOut) validate(path string, errs *fieldErrors) {
+	if c.CheckIn != 0 && c.CheckOut != 0 && c.CheckOut <= c.CheckIn {
+		errs.add(join(path, "checkOut"), "must be after checkIn")
+	}
+}
+
+func (o Occupancies) validate(path string, errs *fieldErrors) {
+	rooms := int64(len(o.RoomOccupancies))
+	for i, room := range o.RoomOccupancies {
+		for j, guest := range room.Guests {
+			if guest.RoomIndex > rooms {
+				errs.add(fmt.Sprintf("%s[%d].guests[%d].roomIndex", join(path, "roomOccupancies"), i, j),
+					"must <= %d, the room count", rooms)
+			}
+		}
+	}
+}
+
+func (g GuestPerRoom) validate(path string, errs *fieldErrors) {
+	if g.AdultCount < 1 {
+		errs.add(join(path, "adultCount"), "must > 0")
+	}
+	for i, age := range g.ChildrenAges {
+		if age < 0 || age > 17 {
+			errs.add(fmt.Sprintf("%s[%d]", join(path, "childrenAges"), i), "must be between 0 and 17")
+		}
+	}
+}
+
+func (g Guest) validate(path string, errs *fieldErrors) {
+	if g.RoomIndex < 1 {
+		errs.add(join(path, "roomIndex"), "must > 0")
+	}
+}
+
+func validateValue(v reflect.Value, path string, errs *fieldErrors) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			validateValue(v.Elem(), path, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, ok := jsonName(field)
+			if !ok {
+				continue
+			}
+			fv := v.Field(i)
+			if field.Tag.Get("required") == "true" && fv.IsZero() {
+				errs.add(join(path, name), "is required")
+				continue
+			}
+			if field.Anonymous {
+				// Fields of embedded structs are flattened, as in encoding/json
+				validateValue(fv, path, errs)
+			} else {
+				validateValue(fv, join(path, name), errs)
+			}
+		}
+		if rule, ok := rules[t]; ok && v.CanInterface() {
+			rule(v, path, errs)
+		}
+	}
+}
+
+func applyDefaults(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			return applyDefaults(v.Elem(), path)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := applyDefaults(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, ok := jsonName(field)
+			if !ok {
+				continue
+			}
+			fv := v.Field(i)
+			if field.Anonymous {
+				name = path
+			} else {
+				name = join(path, name)
+			}
+			if def, ok := tags.Default(field); ok && fv.IsZero() && fv.CanSet() {
+				if err := setDefault(fv, def); err != nil {
+					return fmt.Errorf("invalid default %q of %s: %w", def, name, err)
+				}
+				continue
+			}
+			if err := applyDefaults(fv, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonName returns the JSON name of a field, false if it is not encoded
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return field.Name, true
+}
+
+func setDefault(v reflect.Value, def string) error {
+	if u, ok := v.Addr().Interface().(json.Unmarshaler); ok {
+		return u.UnmarshalJSON([]byte(strconv.Quote(def)))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(def)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(def, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(def, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(def, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(def)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
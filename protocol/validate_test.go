@@ -0,0 +1,93 @@
+package protocol
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	req := &HotelListReq{Occupancies: Occupancies{CountryCode: "AE"}}
+	if err := ApplyDefaults(req); err != nil {
+		t.Fatalf("ApplyDefaults failed: %v", err)
+	}
+	today := types.NewDateIntFromTime(time.Now())
+	if req.CheckIn != today || req.CheckOut != types.NewDateIntFromTime(time.Now().AddDate(0, 0, 7)) {
+		t.Errorf("Expected a stay from today to 7 days later, got %v to %v", req.CheckIn, req.CheckOut)
+	}
+	if req.CountryCode != "AE" || req.ResidencyCode != "US" || req.NationalityCode != "US" {
+		t.Errorf("Expected the country code kept and US codes set, got %+v", req.Occupancies)
+	}
+	if req.PageNum != 1 || req.PageSize != 10 || req.Cursor != 0 {
+		t.Errorf("Expected the first page of 10, got %+v", req.PageReq)
+	}
+	if err := Validate(req); err != nil {
+		t.Errorf("Expected a valid request, got %v", err)
+	}
+
+	if err := ApplyDefaults(HotelListReq{}); err == nil {
+		t.Error("Expected an error for a request not passed by pointer")
+	}
+	var bad struct {
+		Rooms int `default:"many"`
+	}
+	if err := ApplyDefaults(&bad); err == nil || !strings.Contains(err.Error(), "Rooms") {
+		t.Errorf("Expected an invalid default of Rooms, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	req := &HotelRatesReq{
+		CheckInOut: CheckInOut{CheckIn: 20260103, CheckOut: 20260101},
+		Occupancies: Occupancies{
+			RoomOccupancies: []GuestPerRoom{
+				{AdultCount: 2, ChildrenAges: []int64{5, 18}},
+				{AdultCount: -1, Guests: []Guest{{RoomIndex: 4, FirstName: "John"}}},
+				{Guests: []Guest{{RoomIndex: -1, FirstName: "Jane", LastName: "Roe"}}},
+			},
+		},
+	}
+	err := Validate(req)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	var got []string
+	for _, f := range validationErr.Fields {
+		got = append(got, f.Error())
+	}
+	want := []string{
+		"hotelId is required",
+		"checkOut must be after checkIn",
+		"countryCode is required",
+		"residencyCode is required",
+		"nationalityCode is required",
+		"roomOccupancies[0].childrenAges[1] must be between 0 and 17",
+		"roomOccupancies[1].guests[0].lastName is required",
+		"roomOccupancies[1].adultCount must > 0",
+		"roomOccupancies[2].adultCount is required",
+		"roomOccupancies[2].guests[0].roomIndex must > 0",
+		"roomOccupancies[1].guests[0].roomIndex must <= 3, the room count",
+		"sessionId is required",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected fields\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	if err := Validate(&BookReq{RatePkgId: "pkg-1"}); err == nil ||
+		err.Error() != "invalid request: holder is required; guests is required; sessionId is required" {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := Validate(&CancelReq{CustomerReferenceNo: "ref-1"}); err == nil || err.Error() != "invalid request: supplierReferenceNo is required" {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := Validate(&CancelReq{CustomerReferenceNo: "ref-1", SupplierReferenceNo: "sup-1"}); err != nil {
+		t.Errorf("Expected a valid cancellation, got %v", err)
+	}
+	if err := Validate((*BookReq)(nil)); err != nil {
+		t.Errorf("Expected no error for a nil request, got %v", err)
+	}
+}
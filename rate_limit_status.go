@@ -0,0 +1,141 @@
+package hotelbyte
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// RateLimitStatus is the rate limit of the account as last reported by the server
+type RateLimitStatus struct {
+	Limit      int           // requests allowed in the current window, -1 if not reported
+	Remaining  int           // requests left in the current window, -1 if not reported
+	Reset      time.Time     // when the current window resets, zero if not reported
+	RetryAfter time.Duration // wait requested by the last 429 response, 0 if none
+	UpdatedAt  time.Time     // when the status was last reported, zero if never
+}
+
+// Known returns true if the server has reported any rate limit
+func (s RateLimitStatus) Known() bool {
+	return !s.UpdatedAt.IsZero()
+}
+
+// rateLimitHeaders lists the header name variants of limit, remaining and reset, in order of preference
+var rateLimitHeaders = struct {
+	limit, remaining, reset []string
+}{
+	limit:     []string{"X-RateLimit-Limit", "RateLimit-Limit", "X-Rate-Limit-Limit"},
+	remaining: []string{"X-RateLimit-Remaining", "RateLimit-Remaining", "X-Rate-Limit-Remaining"},
+	reset:     []string{"X-RateLimit-Reset", "RateLimit-Reset", "X-Rate-Limit-Reset"},
+}
+
+// epochThreshold tells a reset given as a Unix timestamp from one given as seconds from now
+const epochThreshold = 1_000_000_000
+
+// rateLimitTracker keeps the latest RateLimitStatus
+type rateLimitTracker struct {
+	mu     sync.RWMutex
+	status RateLimitStatus
+}
+
+func newRateLimitTracker() *rateLimitTracker {
+	return &rateLimitTracker{status: RateLimitStatus{Limit: -1, Remaining: -1}}
+}
+
+func (r *rateLimitTracker) Status() RateLimitStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status
+}
+
+// update records the rate limit headers of the response, if any
+func (r *rateLimitTracker) update(resp *types.HttpResponse, now time.Time) {
+	if resp == nil {
+		return
+	}
+	limit, hasLimit := headerInt(resp.Headers, rateLimitHeaders.limit)
+	remaining, hasRemaining := headerInt(resp.Headers, rateLimitHeaders.remaining)
+	reset, hasReset := headerInt(resp.Headers, rateLimitHeaders.reset)
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.Headers.Get("Retry-After"), now)
+	if !hasLimit && !hasRemaining && !hasReset && !hasRetryAfter && resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if hasLimit {
+		r.status.Limit = limit
+	}
+	if hasRemaining {
+		r.status.Remaining = remaining
+	}
+	if hasReset {
+		if reset >= epochThreshold {
+			r.status.Reset = time.Unix(int64(reset), 0)
+		} else {
+			r.status.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+	r.status.RetryAfter = retryAfter
+	r.status.UpdatedAt = now
+}
+
+// throttleDelay returns the wait the server asked for before the next attempt, if any
+func throttleDelay(resp *types.HttpResponse, now time.Time) (time.Duration, bool) {
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if d, ok := parseRetryAfter(resp.Headers.Get("Retry-After"), now); ok {
+		return d, true
+	}
+	// Without Retry-After, an exhausted window tells when requests are accepted again
+	if remaining, ok := headerInt(resp.Headers, rateLimitHeaders.remaining); ok && remaining == 0 {
+		if reset, ok := headerInt(resp.Headers, rateLimitHeaders.reset); ok {
+			if reset >= epochThreshold {
+				return max(time.Unix(int64(reset), 0).Sub(now), 0), true
+			}
+			return time.Duration(reset) * time.Second, true
+		}
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses a Retry-After value given in seconds or as an HTTP-date
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// headerInt returns the first of the headers holding an integer
+func headerInt(h http.Header, names []string) (int, bool) {
+	for _, name := range names {
+		v := strings.TrimSpace(h.Get(name))
+		if v == "" {
+			continue
+		}
+		// Some servers send a list, e.g. "100, 100;w=60", the first item is the current value
+		if i := strings.IndexAny(v, ",;"); i >= 0 {
+			v = strings.TrimSpace(v[:i])
+		}
+		if n, err := strconv.Atoi(v); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,102 @@
+package hotelbyte
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"Thu, 01 Jan 2026 00:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 31 Dec 2025 23:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRetryAfterOn429(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "10")
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "9")
+		w.Header().Set("X-RateLimit-Reset", "30")
+	}))
+	defer srv.Close()
+
+	// The retry must wait for Retry-After (0s), not for InitialDelay
+	client, err := NewClient(
+		WithBaseURL(srv.URL),
+		WithCredentials("key", "secret"),
+		WithRetryConfig(1, time.Hour, time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := client.Do(ctx, &Request{Method: http.MethodGet, Path: "/"})
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	status := client.RateLimitStatus()
+	if !status.Known() || status.Limit != 10 || status.Remaining != 9 {
+		t.Errorf("Unexpected rate limit status %+v", status)
+	}
+	if until := time.Until(status.Reset); until <= 0 || until > 30*time.Second {
+		t.Errorf("Expected reset within 30s, got %v", until)
+	}
+}
+
+func TestThrottleBeyondMaxDelay(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"), WithRetryConfig(3, time.Millisecond, time.Second))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	// An hour is longer than MaxDelay: the 429 is returned at once
+	start := time.Now()
+	resp, _ := client.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/"})
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests || atomic.LoadInt32(&attempts) != 1 {
+		t.Errorf("Expected a single 429, got %v after %d attempts", resp, atomic.LoadInt32(&attempts))
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected no wait, waited %v", elapsed)
+	}
+}
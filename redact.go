@@ -0,0 +1,345 @@
+package hotelbyte
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/hotelbyte-com/sdk-go/internal/jsonfields"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// PII categories used in `pii:"..."` struct tags of the protocol types
+const (
+	PIIName        = "name"
+	PIIEmail       = "email"
+	PIIPhone       = "phone"
+	PIINationality = "nationality"
+	PIISecret      = "secret" // credentials such as AppSecret and tickets
+)
+
+// RedactMode is how a sensitive value is redacted
+type RedactMode int
+
+const (
+	// RedactMask replaces the value with a partial mask, e.g. "J***" or "j***@example.com"; secrets are fully masked
+	RedactMask RedactMode = iota
+	// RedactHash replaces the value with a stable hash, so that records can still be correlated
+	RedactHash
+	// RedactDrop removes the field
+	RedactDrop
+	// RedactKeep leaves the value as is
+	RedactKeep
+)
+
+func (m RedactMode) String() string {
+	switch m {
+	case RedactMask:
+		return "mask"
+	case RedactHash:
+		return "hash"
+	case RedactDrop:
+		return "drop"
+	case RedactKeep:
+		return "keep"
+	default:
+		return "unknown"
+	}
+}
+
+// RedactConfig represents redaction configuration
+type RedactConfig struct {
+	// Mode applies to every category not listed in Categories
+	Mode RedactMode
+	// Categories sets the RedactMode per PII category
+	Categories map[string]RedactMode
+	// HashKey keys the HMAC of RedactHash, so that hashes cannot be reversed by guessing; empty uses plain SHA-256
+	HashKey []byte
+	// Keys are JSON keys, matched case-insensitively at any depth, redacted as PIISecret in addition
+	// to the tagged fields, e.g. for fields of types the Redactor does not know
+	Keys []string
+}
+
+// Redactor serialises values for logs, errors, audit trails or support bundles with the fields tagged
+// `pii:"<category>"` masked, hashed or dropped. Tags are honoured at any depth, through pointers,
+// slices, maps and embedded structs.
+type Redactor struct {
+	config RedactConfig
+	keys   map[string]bool
+}
+
+// NewRedactor creates a Redactor
+func NewRedactor(config RedactConfig) *Redactor {
+	c := config
+	c.Categories = make(map[string]RedactMode, len(config.Categories))
+	for k, v := range config.Categories {
+		c.Categories[k] = v
+	}
+	c.HashKey = append([]byte(nil), config.HashKey...)
+	c.Keys = append([]string(nil), config.Keys...)
+	keys := make(map[string]bool, len(c.Keys))
+	for _, k := range c.Keys {
+		keys[strings.ToLower(k)] = true
+	}
+	return &Redactor{config: c, keys: keys}
+}
+
+// defaultRedactor masks every category
+var defaultRedactor = NewRedactor(RedactConfig{})
+
+// ToRedactedJSON marshals v to a JSON string with every PII field masked, or "" if it cannot be marshaled
+func ToRedactedJSON(v interface{}) string {
+	return defaultRedactor.JSON(v)
+}
+
+// JSON returns the redacted JSON of v, or "" if it cannot be marshaled
+func (r *Redactor) JSON(v any) string {
+	if v == nil {
+		return ""
+	}
+	tree, err := r.Value(v)
+	if err != nil {
+		return ""
+	}
+	s, err := sonic.MarshalString(tree)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// Value returns v as a generic JSON value (maps, slices, strings, numbers) with PII fields redacted
+func (r *Redactor) Value(v any) (any, error) {
+	raw, err := sonic.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+	return r.value(raw, reflect.TypeOf(v))
+}
+
+// RedactJSON redacts raw JSON holding a value of the type of like, e.g. a response body
+// with like set to (*types.Response[protocol.BookResp])(nil). Keys are redacted whatever like is.
+func (r *Redactor) RedactJSON(raw []byte, like any) ([]byte, error) {
+	tree, err := r.value(raw, reflect.TypeOf(like))
+	if err != nil {
+		return nil, err
+	}
+	return sonic.Marshal(tree)
+}
+
+const redacted = "***"
+
+// redactAPI decodes numbers as json.Number, so that large IDs survive redaction
+var redactAPI = sonic.Config{UseNumber: true}.Froze()
+
+func (r *Redactor) value(raw []byte, t reflect.Type) (any, error) {
+	var tree any
+	if err := redactAPI.Unmarshal(raw, &tree); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+	tree = r.apply(tree, schemaOf(t))
+	if len(r.keys) > 0 {
+		tree = r.applyKeys(tree)
+	}
+	return tree, nil
+}
+
+// applyKeys redacts the values of Keys at any depth
+func (r *Redactor) applyKeys(tree any) any {
+	switch t := tree.(type) {
+	case map[string]any:
+		for k, v := range t {
+			if !r.keys[strings.ToLower(k)] {
+				t[k] = r.applyKeys(v)
+			} else if mode := r.mode(PIISecret); mode == RedactDrop {
+				delete(t, k)
+			} else {
+				t[k] = r.redact(PIISecret, mode, v)
+			}
+		}
+	case []any:
+		for i, v := range t {
+			t[i] = r.applyKeys(v)
+		}
+	}
+	return tree
+}
+
+func (r *Redactor) mode(category string) RedactMode {
+	if m, ok := r.config.Categories[category]; ok {
+		return m
+	}
+	return r.config.Mode
+}
+
+func (r *Redactor) apply(tree any, s *piiSchema) any {
+	if s == nil {
+		return tree
+	}
+	switch t := tree.(type) {
+	case map[string]any:
+		for key, child := range s.fields {
+			v, ok := t[key]
+			if !ok {
+				continue
+			}
+			if child.category == "" {
+				t[key] = r.apply(v, child)
+				continue
+			}
+			switch mode := r.mode(child.category); mode {
+			case RedactDrop:
+				delete(t, key)
+			default:
+				t[key] = r.redact(child.category, mode, v)
+			}
+		}
+		if s.elem != nil {
+			for key, v := range t {
+				t[key] = r.apply(v, s.elem)
+			}
+		}
+	case []any:
+		for i, v := range t {
+			t[i] = r.apply(v, s.elem)
+		}
+	}
+	return tree
+}
+
+// redact returns the redacted form of a leaf value
+func (r *Redactor) redact(category string, mode RedactMode, v any) any {
+	if v == nil {
+		return nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		b, _ := sonic.Marshal(v)
+		s = string(b)
+	}
+	if s == "" {
+		return v
+	}
+	switch mode {
+	case RedactKeep:
+		return v
+	case RedactHash:
+		var h []byte
+		if len(r.config.HashKey) > 0 {
+			mac := hmac.New(sha256.New, r.config.HashKey)
+			mac.Write([]byte(s))
+			h = mac.Sum(nil)
+		} else {
+			sum := sha256.Sum256([]byte(s))
+			h = sum[:]
+		}
+		return "sha256:" + hex.EncodeToString(h[:8])
+	default:
+		return mask(category, s)
+	}
+}
+
+// mask keeps just enough of a value to tell records apart by eye
+func mask(category, s string) string {
+	switch category {
+	case PIISecret:
+		return redacted
+	case PIIEmail:
+		if at := strings.LastIndexByte(s, '@'); at > 0 {
+			return firstRune(s[:at]) + redacted + s[at:]
+		}
+	case PIIPhone:
+		if len(s) > 4 {
+			return redacted + s[len(s)-4:]
+		}
+		return redacted
+	}
+	return firstRune(s) + redacted
+}
+
+func firstRune(s string) string {
+	for _, r := range s {
+		return string(r)
+	}
+	return ""
+}
+
+// piiSchema tells where the PII fields of a type are in its JSON
+type piiSchema struct {
+	category string                // PII category of a tagged field
+	fields   map[string]*piiSchema // fields of a struct holding PII, by JSON key
+	elem     *piiSchema            // elements of a slice, array or map holding PII
+}
+
+// piiSchemas caches the schema of every type seen, nil if the type holds no PII
+var piiSchemas sync.Map // reflect.Type -> *piiSchema
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	extraType     = reflect.TypeOf(types.Extra(nil))
+)
+
+func schemaOf(t reflect.Type) *piiSchema {
+	if t == nil {
+		return nil
+	}
+	if s, ok := piiSchemas.Load(t); ok {
+		return s.(*piiSchema)
+	}
+	s := buildSchema(t, map[reflect.Type]bool{})
+	piiSchemas.Store(t, s)
+	return s
+}
+
+func buildSchema(t reflect.Type, visiting map[reflect.Type]bool) *piiSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if visiting[t] || opaque(t) {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	switch t.Kind() {
+	case reflect.Struct:
+		fields := make(map[string]*piiSchema)
+		for _, f := range jsonfields.Of(t) {
+			if category := f.Tag.Get("pii"); category != "" {
+				fields[f.Name] = &piiSchema{category: category}
+			} else if s := buildSchema(f.Type, visiting); s != nil {
+				fields[f.Name] = s
+			}
+		}
+		if len(fields) == 0 {
+			return nil
+		}
+		return &piiSchema{fields: fields}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if elem := buildSchema(t.Elem(), visiting); elem != nil {
+			return &piiSchema{elem: elem}
+		}
+	}
+	return nil
+}
+
+// opaque reports whether t encodes otherwise than its fields tell, e.g. a date. The structs holding
+// a types.Extra encode their fields, and the extra members after them.
+func opaque(t reflect.Type) bool {
+	if !t.Implements(jsonMarshaler) && !reflect.PointerTo(t).Implements(jsonMarshaler) {
+		return false
+	}
+	if t.Kind() == reflect.Struct {
+		if f, ok := t.FieldByName("Extra"); ok && f.Type == extraType {
+			return false
+		}
+	}
+	return true
+}
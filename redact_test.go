@@ -0,0 +1,88 @@
+package hotelbyte
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+func testBookReq() *protocol.BookReq {
+	return &protocol.BookReq{
+		CustomerReferenceNo: "ref-1",
+		RatePkgId:           "pkg-1",
+		Holder: protocol.Holder{
+			FirstName: "John",
+			LastName:  "Doe",
+			Email:     "john@example.com",
+			Phone:     protocol.Phone{CountryCode: "AE", Number: "525757249"},
+		},
+		Guests:        []protocol.Guest{{RoomIndex: 1, FirstName: "Jane", LastName: "Roe", NationalityCode: "US"}},
+		SessionOption: protocol.SessionOption{SessionId: "session-1"},
+	}
+}
+
+func TestRedactorMask(t *testing.T) {
+	v, err := NewRedactor(RedactConfig{}).Value(testBookReq())
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	m := v.(map[string]any)
+	holder := m["holder"].(map[string]any)
+	want := map[string]any{
+		"firstName": "J***",
+		"lastName":  "D***",
+		"email":     "j***@example.com",
+		"phone":     map[string]any{"countryCode": "AE", "number": "***7249"},
+	}
+	if !reflect.DeepEqual(holder, want) {
+		t.Errorf("Expected holder %v, got %v", want, holder)
+	}
+	guest := m["guests"].([]any)[0].(map[string]any)
+	if guest["firstName"] != "J***" || guest["nationalityCode"] != "U***" || guest["roomIndex"] != json.Number("1") {
+		t.Errorf("Unexpected guest %v", guest)
+	}
+	if m["customerReferenceNo"] != "ref-1" {
+		t.Errorf("Expected untagged fields to be kept, got %v", m["customerReferenceNo"])
+	}
+}
+
+func TestRedactorModes(t *testing.T) {
+	r := NewRedactor(RedactConfig{
+		Mode:       RedactHash,
+		Categories: map[string]RedactMode{PIIEmail: RedactDrop, PIINationality: RedactKeep},
+		HashKey:    []byte("k"),
+	})
+	a := r.JSON(testBookReq())
+	if strings.Contains(a, "John") || strings.Contains(a, "email") || !strings.Contains(a, `"nationalityCode":"US"`) {
+		t.Errorf("Unexpected redaction %s", a)
+	}
+	if !strings.Contains(a, `"firstName":"sha256:`) {
+		t.Errorf("Expected hashed names, got %s", a)
+	}
+	if b := r.JSON(testBookReq()); a != b {
+		t.Errorf("Expected stable hashes, got %s and %s", a, b)
+	}
+	if c := NewRedactor(RedactConfig{Mode: RedactHash, HashKey: []byte("other")}).JSON(testBookReq()); strings.Contains(a, c[strings.Index(c, "sha256:"):][:23]) {
+		t.Errorf("Expected hashes to depend on the key")
+	}
+}
+
+func TestToRedactedJSONSecret(t *testing.T) {
+	s := ToRedactedJSON(&protocol.AuthReq{AppKey: "key", AppSecret: "top-secret", TTL: 60})
+	if s != `{"appKey":"key","appSecret":"***","ttl":60}` {
+		t.Errorf("Unexpected JSON %s", s)
+	}
+}
+
+func TestRedactorKeepsLargeNumbers(t *testing.T) {
+	out, err := NewRedactor(RedactConfig{}).RedactJSON([]byte(`{"id":9007199254740993,"ticket":"t"}`), nil)
+	if err != nil {
+		t.Fatalf("RedactJSON failed: %v", err)
+	}
+	if string(out) != `{"id":9007199254740993,"ticket":"t"}` {
+		t.Errorf("Unexpected JSON %s", out)
+	}
+}
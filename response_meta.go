@@ -0,0 +1,72 @@
+package hotelbyte
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// ResponseMeta describes how a call was answered, see WithResponseMeta
+type ResponseMeta struct {
+	StatusCode   int           // HTTP status of the last response, 0 if none was received
+	TraceID      string        // Trace-Id response header
+	SessionID    string        // Session-Id response header
+	ServerCostMs int64         // Server-Cost-Milliseconds response header, -1 if missing
+	Attempts     int           // requests sent, retries and re-authentication included
+	Latency      time.Duration // time spent by the client on the call
+	Header       http.Header   // headers of the last response
+}
+
+// WithResponseMeta fills meta once the call returns, whether it succeeded or not
+func WithResponseMeta(meta *ResponseMeta) CallOption {
+	return func(c *CallConfig) error {
+		if meta == nil {
+			return fmt.Errorf("nil response meta")
+		}
+		c.ResponseMeta = meta
+		return nil
+	}
+}
+
+// callStats counts the attempts of a call, shared through the context with the transport.
+// Requests to other paths, e.g. for a ticket, are not counted.
+type callStats struct {
+	path     string
+	attempts int
+}
+
+type callStatsKey struct{}
+
+func withCallStats(ctx context.Context, stats *callStats) context.Context {
+	return context.WithValue(ctx, callStatsKey{}, stats)
+}
+
+// countAttempt records an attempt to path in ctx, if it collects the stats of a call to path
+func countAttempt(ctx context.Context, path string) {
+	if stats, ok := ctx.Value(callStatsKey{}).(*callStats); ok && stats.path == path {
+		stats.attempts++
+	}
+}
+
+// fill sets the meta from the last response of the call
+func (m *ResponseMeta) fill(resp *types.HttpResponse, stats *callStats, start time.Time) {
+	*m = ResponseMeta{
+		ServerCostMs: -1,
+		Attempts:     stats.attempts,
+		Latency:      time.Since(start),
+	}
+	if resp == nil {
+		return
+	}
+	m.StatusCode = resp.StatusCode
+	m.Header = resp.Headers
+	m.TraceID = resp.Headers.Get("Trace-Id")
+	m.SessionID = resp.Headers.Get("Session-Id")
+	if v, err := strconv.ParseInt(resp.Headers.Get("Server-Cost-Milliseconds"), 10, 64); err == nil {
+		m.ServerCostMs = v
+	}
+}
@@ -0,0 +1,48 @@
+package hotelbyte
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+func TestResponseMeta(t *testing.T) {
+	var searches int32
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&searches, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Trace-Id", "trace-1")
+		w.Header().Set("Session-Id", "session-1")
+		w.Header().Set("Server-Cost-Milliseconds", "12")
+		fmt.Fprint(w, `{"code":0,"data":{}}`)
+	})
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"), WithRetryConfig(1, time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	var meta ResponseMeta
+	resp, err := client.HotelList(context.Background(), &protocol.HotelListReq{}, WithResponseMeta(&meta))
+	if err != nil {
+		t.Fatalf("HotelList failed: %v", err)
+	}
+	if meta.StatusCode != http.StatusOK || meta.TraceID != "trace-1" || meta.SessionID != "session-1" || meta.ServerCostMs != 12 {
+		t.Errorf("Unexpected meta %+v", meta)
+	}
+	// The ticket request is not an attempt of the call
+	if meta.Attempts != 2 || meta.Latency <= 0 {
+		t.Errorf("Expected 2 attempts and a latency, got %d and %v", meta.Attempts, meta.Latency)
+	}
+	if resp.Header.TraceId != "trace-1" {
+		t.Errorf("Expected the CommonHeader to be filled, got %+v", resp.Header)
+	}
+}
@@ -0,0 +1,170 @@
+package hotelbyte
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// RetryClass tells which failures of an endpoint may be retried
+type RetryClass int
+
+const (
+	// RetryIdempotent retries network errors, 429 Too Many Requests and 5xx Server Errors.
+	// It is the default, suitable for searches and queries.
+	RetryIdempotent RetryClass = iota
+	// RetryPreSend retries only failures where the request provably never reached the server,
+	// e.g. DNS failures or refused connections. Used for Book and Cancel, where a blind retry
+	// after a timeout could duplicate the operation.
+	RetryPreSend
+	// RetryNever disables retries
+	RetryNever
+)
+
+func (c RetryClass) String() string {
+	switch c {
+	case RetryIdempotent:
+		return "idempotent"
+	case RetryPreSend:
+		return "pre-send"
+	case RetryNever:
+		return "never"
+	default:
+		return "unknown"
+	}
+}
+
+// DefaultRetryEndpoints returns the retry classes of endpoints that are not safe to retry blindly
+func DefaultRetryEndpoints() map[string]RetryClass {
+	return map[string]RetryClass{
+		protocol.PathBook:   RetryPreSend,
+		protocol.PathCancel: RetryPreSend,
+	}
+}
+
+// classOf returns the retry class of the endpoint
+func (c RetryConfig) classOf(path string) RetryClass {
+	if class, ok := c.Endpoints[path]; ok {
+		return class
+	}
+	return RetryIdempotent
+}
+
+// backoff returns the wait before the given retry, starting from 1
+func (c RetryConfig) backoff(retry int) time.Duration {
+	factor := c.BackoffFactor
+	if factor < 1 {
+		factor = 1
+	}
+	delay := float64(c.InitialDelay) * math.Pow(factor, float64(retry-1))
+	if c.MaxDelay > 0 && delay > float64(c.MaxDelay) {
+		return c.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// ReconcileFunc checks whether a failed request took effect on the server anyway.
+// If so, it returns a response equivalent to the one that was lost and true.
+type ReconcileFunc func(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, bool)
+
+// isRetryableFailure reports whether the attempt failed in a way that is worth retrying:
+// network error || 429 Too Many Requests || 5xx Server Error
+func isRetryableFailure(resp *types.HttpResponse, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// isUnknownOutcome reports whether a failed attempt may have taken effect on the server: the request
+// may have been sent and its response lost, e.g. on a timeout or a connection reset, or the server
+// failed while handling it with a 5xx. A 429 and the failures before sending, e.g. a rate limiter
+// wait or a refused connection, did not take effect.
+func isUnknownOutcome(resp *types.HttpResponse, err error) bool {
+	if err != nil {
+		var transportErr *TransportError
+		return errors.As(err, &transportErr) && !isPreSendError(err)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// isPreSendError reports whether the request provably never reached the server
+func isPreSendError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// allowsRetry reports whether the retry class permits retrying the failure
+func (c RetryClass) allowsRetry(err error) bool {
+	switch c {
+	case RetryIdempotent:
+		return true
+	case RetryPreSend:
+		return isPreSendError(err)
+	default:
+		return false
+	}
+}
+
+type attemptKey struct{}
+
+// withAttempt records the attempt number in the context
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// AttemptFromContext returns the attempt number of the request being sent, starting from 1.
+// Middlewares can use it to tell retries apart.
+func AttemptFromContext(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+	return 1
+}
+
+// detachCall returns ctx without the values of the call it was made for, its RetryPolicy, attempt
+// and ResponseMeta stats, for the requests sent on behalf of the call. Its cancellation, deadline
+// and span are kept.
+func detachCall(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+type detachedContext struct {
+	context.Context
+}
+
+func (c detachedContext) Value(key any) any {
+	switch key.(type) {
+	case retryPolicyKey, attemptKey, callStatsKey:
+		return nil
+	}
+	return c.Context.Value(key)
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,138 @@
+package hotelbyte
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+func TestRetryConfigBackoff(t *testing.T) {
+	c := RetryConfig{InitialDelay: time.Second, MaxDelay: 5 * time.Second, BackoffFactor: 2}
+	for retry, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second} {
+		if got := c.backoff(retry); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", retry, got, want)
+		}
+	}
+}
+
+func newRetryTestClient(t *testing.T, url string) *Client {
+	client, err := NewClient(
+		WithBaseURL(url),
+		WithCredentials("key", "secret"),
+		WithRetryConfig(2, time.Millisecond, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestRetryEndpointClasses(t *testing.T) {
+	var searches, books, queries int32
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case protocol.PathHotelList:
+			if atomic.AddInt32(&searches, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"code":0,"data":{}}`)
+		case protocol.PathBook:
+			atomic.AddInt32(&books, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		case protocol.PathQueryOrders:
+			if atomic.AddInt32(&queries, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"code":0,"data":{"orders":[{"status":2,"customerReferenceNo":"ref-1"}]}}`)
+		}
+	})
+	client := newRetryTestClient(t, srv.URL)
+	ctx := context.Background()
+
+	if _, err := client.HotelList(ctx, &protocol.HotelListReq{}); err != nil {
+		t.Fatalf("HotelList failed: %v", err)
+	}
+	if searches != 3 {
+		t.Errorf("Expected 3 search attempts, got %d", searches)
+	}
+
+	// The lookup is retried as a query, whatever the RetryPolicy of Book
+	resp, err := client.Book(ctx, testBookReq(), WithRetryPolicy(RetryPolicy{Class: RetryNever}))
+	if err != nil {
+		t.Fatalf("Book failed: %v", err)
+	}
+	if books != 1 || queries != 2 {
+		t.Errorf("Expected 1 book attempt and 2 reconciliation attempts, got %d and %d", books, queries)
+	}
+	if resp.HotelOrder == nil || resp.HotelOrder.Status != protocol.OrderStatus_Confirmed {
+		t.Errorf("Expected reconciled confirmed order, got %+v", resp.HotelOrder)
+	}
+}
+
+func TestReconcileUnknownOutcomeOnly(t *testing.T) {
+	var books, queries int32
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case protocol.PathBook:
+			atomic.AddInt32(&books, 1)
+			w.WriteHeader(http.StatusTooManyRequests)
+		case protocol.PathQueryOrders:
+			atomic.AddInt32(&queries, 1)
+			fmt.Fprint(w, `{"code":0,"data":{"orders":[]}}`)
+		}
+	})
+	client := newRetryTestClient(t, srv.URL)
+
+	// A 429 was refused before the booking, there is nothing to reconcile
+	if _, err := client.Book(context.Background(), testBookReq()); err == nil {
+		t.Fatal("Expected error, but got nil")
+	}
+	if books != 1 || queries != 0 {
+		t.Errorf("Expected 1 book attempt and no reconciliation, got %d and %d", books, queries)
+	}
+}
+
+func TestRetryPreSend(t *testing.T) {
+	// A closed port refuses the connection, so the request never reaches a server
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	var attempts int32
+	counter := func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, error) {
+			atomic.AddInt32(&attempts, 1)
+			return next(ctx, req)
+		}
+	}
+	client, err := NewClient(
+		WithBaseURL("http://"+addr),
+		WithCredentials("key", "secret"),
+		WithRetryConfig(2, time.Millisecond, time.Millisecond),
+		WithMiddleware(counter),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Do(context.Background(), &Request{Method: http.MethodPost, Path: protocol.PathCancel}); err == nil {
+		t.Fatal("Expected error, but got nil")
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
@@ -0,0 +1,72 @@
+package hotelbyte
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// Base URLs of the HotelByte API
+const (
+	ProductionBaseURL = "https://api.hotelbyte.com"
+	SandboxBaseURL    = "https://api-test.hotelbyte.com"
+)
+
+// ErrTestFlagsInProduction is returned instead of sending a request carrying test flags to the production API
+var ErrTestFlagsInProduction = errors.New("test flags must not be sent to the production API")
+
+// WithDefaultTestFlags sets the test flags of every request that carries none of its own
+func WithDefaultTestFlags(flags protocol.TestFlags) ClientOption {
+	return func(c *Config) error {
+		if err := flags.Validate(); err != nil {
+			return fmt.Errorf("invalid test flags: %w", err)
+		}
+		c.TestFlags = flags
+		return nil
+	}
+}
+
+// isProduction reports whether baseURL points at the production API
+func isProduction(baseURL string) bool {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	prod, _ := url.Parse(ProductionBaseURL)
+	return strings.EqualFold(u.Hostname(), prod.Hostname())
+}
+
+// withTestFlags returns a copy of req carrying the default flags, unless it carries its own
+func withTestFlags(req *types.HttpRequest, flags protocol.TestFlags) *types.HttpRequest {
+	if flags.IsZero() || hasTestFlags(req) {
+		return req
+	}
+	r := *req
+	r.Headers = make(map[string]string, len(req.Headers)+1)
+	for k, v := range req.Headers {
+		r.Headers[k] = v
+	}
+	r.Headers["Test"] = flags.String()
+	return &r
+}
+
+// testFlagger is implemented by the requests embedding protocol.TestOption
+type testFlagger interface {
+	Flags() (protocol.TestFlags, error)
+}
+
+// hasTestFlags reports whether the request carries test flags, in the Test header or in the body
+func hasTestFlags(req *types.HttpRequest) bool {
+	if req.Headers["Test"] != "" {
+		return true
+	}
+	if o, ok := req.Body.(testFlagger); ok {
+		flags, err := o.Flags()
+		return err != nil || !flags.IsZero()
+	}
+	return false
+}
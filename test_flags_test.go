@@ -0,0 +1,68 @@
+package hotelbyte
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+)
+
+func TestDefaultTestFlags(t *testing.T) {
+	var test string
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		test = r.Header.Get("Test")
+		fmt.Fprint(w, `{"code":0,"data":{}}`)
+	})
+
+	flags := protocol.TestFlags{Scenario: "soldOut"}
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"), WithDefaultTestFlags(flags))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	if _, err := client.QueryOrders(ctx, &protocol.QueryOrdersReq{}); err != nil || test != "scenario=soldOut" {
+		t.Errorf("Expected the default flags, got %q, %v", test, err)
+	}
+	// Flags of the request win
+	req := &protocol.QueryOrdersReq{TestOption: protocol.TestFlags{Scenario: "slow"}.Option()}
+	if _, err := client.QueryOrders(ctx, req); err != nil || test != "scenario=slow" {
+		t.Errorf("Expected the flags of the request, got %q, %v", test, err)
+	}
+
+	_, err = NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"), WithDefaultTestFlags(protocol.TestFlags{Ratio: -1}))
+	if err == nil || errors.Is(err, ErrTestFlagsInProduction) {
+		t.Errorf("Expected invalid test flags to be rejected, got %v", err)
+	}
+}
+
+func TestTestFlagsProductionGuard(t *testing.T) {
+	flags := protocol.TestFlags{Scenario: "soldOut"}
+	_, err := NewClient(WithCredentials("key", "secret"), WithDefaultTestFlags(flags))
+	if !errors.Is(err, ErrTestFlagsInProduction) {
+		t.Errorf("Expected ErrTestFlagsInProduction, got %v", err)
+	}
+
+	sent := false
+	client, err := NewClient(WithCredentials("key", "secret"), WithBaseURL("https://API.hotelbyte.com/"),
+		WithHTTPTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+			sent = true
+			return nil, errors.New("unexpected request")
+		})))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+	_, err = client.Do(context.Background(), &Request{Method: http.MethodPost, Path: protocol.PathHotelList, Body: &protocol.HotelListReq{TestOption: flags.Option()}})
+	if !errors.Is(err, ErrTestFlagsInProduction) || sent {
+		t.Errorf("Expected ErrTestFlagsInProduction without sending, got %v", err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,148 @@
+package hotelbyte
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Token is an authentication ticket together with its expiry
+type Token struct {
+	Ticket string    `json:"ticket"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// TokenStore persists tickets keyed by AppKey, so they can be shared across clients, processes and restarts.
+// Implementations must be safe for concurrent use.
+type TokenStore interface {
+	// Get returns the stored ticket, or nil if there is none
+	Get(ctx context.Context, appKey string) (*Token, error)
+	// Put stores the ticket, replacing any previous one
+	Put(ctx context.Context, appKey string, token *Token) error
+	// Invalidate removes the stored ticket
+	Invalidate(ctx context.Context, appKey string) error
+}
+
+// MemoryTokenStore keeps tickets in process memory. Share one instance between clients to share tickets.
+type MemoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]Token
+}
+
+// NewMemoryTokenStore creates a new in-memory token store
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: make(map[string]Token)}
+}
+
+func (m *MemoryTokenStore) Get(_ context.Context, appKey string) (*Token, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	token, ok := m.tokens[appKey]
+	if !ok {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+func (m *MemoryTokenStore) Put(_ context.Context, appKey string, token *Token) error {
+	if token == nil {
+		return fmt.Errorf("nil token")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[appKey] = *token
+	return nil
+}
+
+func (m *MemoryTokenStore) Invalidate(_ context.Context, appKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, appKey)
+	return nil
+}
+
+// FileTokenStore keeps one file per AppKey in a directory.
+// Files are written atomically with mode 0600, so several processes can share the directory.
+type FileTokenStore struct {
+	dir string
+}
+
+// NewFileTokenStore creates a file-backed token store, creating dir with mode 0700 if needed
+func NewFileTokenStore(dir string) (*FileTokenStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("empty token store dir")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create token store dir: %w", err)
+	}
+	return &FileTokenStore{dir: dir}, nil
+}
+
+// path returns the file of the AppKey; the key is hashed so it can't escape dir
+func (f *FileTokenStore) path(appKey string) string {
+	sum := sha256.Sum256([]byte(appKey))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func (f *FileTokenStore) Get(_ context.Context, appKey string) (*Token, error) {
+	data, err := os.ReadFile(f.path(appKey))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("invalid token file: %w", err)
+	}
+	return &token, nil
+}
+
+func (f *FileTokenStore) Put(_ context.Context, appKey string, token *Token) error {
+	if token == nil {
+		return fmt.Errorf("nil token")
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file in the same dir and rename it, so readers never see a partial file
+	tmp, err := os.CreateTemp(f.dir, ".token-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp token file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(appKey))
+}
+
+func (f *FileTokenStore) Invalidate(_ context.Context, appKey string) error {
+	err := os.Remove(f.path(appKey))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package hotelbyte
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileTokenStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileTokenStore failed: %v", err)
+	}
+
+	if token, err := store.Get(ctx, "key"); err != nil || token != nil {
+		t.Fatalf("Expected empty store, got %v, %v", token, err)
+	}
+
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := store.Put(ctx, "key", &Token{Ticket: "ticket", Expiry: expiry}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	info, err := os.Stat(store.path("key"))
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("Expected mode 0600, got %o", perm)
+	}
+
+	token, err := store.Get(ctx, "key")
+	if err != nil || token == nil {
+		t.Fatalf("Get failed: %v, %v", token, err)
+	}
+	if token.Ticket != "ticket" || !token.Expiry.Equal(expiry) {
+		t.Errorf("Unexpected token %+v", token)
+	}
+
+	if err := store.Invalidate(ctx, "key"); err != nil {
+		t.Fatalf("Invalidate failed: %v", err)
+	}
+	if token, _ := store.Get(ctx, "key"); token != nil {
+		t.Errorf("Expected token to be removed, got %+v", token)
+	}
+}
+
+func TestAuthManagerSharesTokenStore(t *testing.T) {
+	var calls int32
+	fetch := func(ctx context.Context) (string, time.Time, error) {
+		atomic.AddInt32(&calls, 1)
+		return "ticket", time.Now().Add(time.Hour), nil
+	}
+	store := NewMemoryTokenStore()
+
+	first := newAuthManager(fetch, store, "key")
+	defer first.close()
+	second := newAuthManager(fetch, store, "key")
+	defer second.close()
+
+	if _, err := first.ensure(context.Background()); err != nil {
+		t.Fatalf("ensure failed: %v", err)
+	}
+	if _, err := second.ensure(context.Background()); err != nil {
+		t.Fatalf("ensure failed: %v", err)
+	}
+	if second.Token() != "ticket" {
+		t.Errorf("Expected shared token 'ticket', got '%s'", second.Token())
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected 1 ticket request, got %d", n)
+	}
+
+	// A forced refresh must not adopt the ticket being replaced
+	if _, err := second.refresh(context.Background(), second.Token()); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("Expected 2 ticket requests, got %d", n)
+	}
+}
@@ -0,0 +1,117 @@
+package hotelbyte
+
+import (
+	"context"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+// instrumentationName is the name of the tracer
+const instrumentationName = "github.com/hotelbyte-com/sdk-go"
+
+// Span attribute keys
+const (
+	AttrEndpoint            = attribute.Key("hotelbyte.endpoint")
+	AttrHotelIDCount        = attribute.Key("hotelbyte.hotel_id_count")
+	AttrSessionID           = attribute.Key("hotelbyte.session_id")
+	AttrCustomerReferenceNo = attribute.Key("hotelbyte.customer_reference_no")
+	AttrBizErrorCode        = attribute.Key("hotelbyte.biz_error_code")
+	AttrServerTraceID       = attribute.Key("hotelbyte.server.trace_id")
+	AttrServerSessionID     = attribute.Key("hotelbyte.server.session_id")
+	AttrServerCostMs        = attribute.Key("hotelbyte.server.cost_ms")
+	AttrAttempt             = attribute.Key("hotelbyte.attempt")
+	AttrHTTPMethod          = attribute.Key("http.request.method")
+	AttrHTTPStatusCode      = attribute.Key("http.response.status_code")
+)
+
+// TracingConfig represents OpenTelemetry tracing configuration
+type TracingConfig struct {
+	// TracerProvider creates the spans; nil disables tracing. The global provider is never used.
+	TracerProvider trace.TracerProvider
+	// Propagator injects the trace context into outgoing headers; defaults to W3C trace context
+	Propagator propagation.TextMapPropagator
+}
+
+// tracer returns the configured tracer, or a no-op one
+func (c TracingConfig) tracer() trace.Tracer {
+	if c.TracerProvider == nil {
+		return noop.NewTracerProvider().Tracer(instrumentationName)
+	}
+	return c.TracerProvider.Tracer(instrumentationName)
+}
+
+// propagator returns the configured propagator, or W3C trace context
+func (c TracingConfig) propagator() propagation.TextMapPropagator {
+	if c.Propagator == nil {
+		return propagation.TraceContext{}
+	}
+	return c.Propagator
+}
+
+// startSpan starts the span of a Client method
+func (s *Client) startSpan(ctx context.Context, name, endpoint string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "hotelbyte."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, AttrEndpoint.String(endpoint))...),
+	)
+}
+
+// endSpan records the outcome of the call and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		if bizErr, ok := types.CastBizErr(err); ok {
+			span.SetAttributes(AttrBizErrorCode.Int(int(bizErr.Code)))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracingMiddleware injects the trace context into outgoing headers and records every attempt and
+// the server's tracing headers on the span of the call
+func tracingMiddleware(propagator propagation.TextMapPropagator) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, error) {
+			span := trace.SpanFromContext(ctx)
+
+			r := *req
+			r.Headers = make(map[string]string, len(req.Headers)+2)
+			for k, v := range req.Headers {
+				r.Headers[k] = v
+			}
+			propagator.Inject(ctx, propagation.MapCarrier(r.Headers))
+
+			resp, err := next(ctx, &r)
+
+			attrs := []attribute.KeyValue{
+				AttrHTTPMethod.String(req.Method),
+				AttrAttempt.Int(AttemptFromContext(ctx)),
+			}
+			if resp != nil {
+				attrs = append(attrs, AttrHTTPStatusCode.Int(resp.StatusCode))
+				if v := resp.Headers.Get("Trace-Id"); v != "" {
+					attrs = append(attrs, AttrServerTraceID.String(v))
+				}
+				if v := resp.Headers.Get("Session-Id"); v != "" {
+					attrs = append(attrs, AttrServerSessionID.String(v))
+				}
+				if v, err := strconv.ParseInt(resp.Headers.Get("Server-Cost-Milliseconds"), 10, 64); err == nil {
+					attrs = append(attrs, AttrServerCostMs.Int64(v))
+				}
+			}
+			span.SetAttributes(attrs...)
+			if err != nil {
+				span.AddEvent("attempt failed", trace.WithAttributes(AttrAttempt.Int(AttemptFromContext(ctx))))
+			}
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package hotelbyte
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/hotelbyte-com/sdk-go/protocol"
+	"github.com/hotelbyte-com/sdk-go/protocol/types"
+)
+
+func TestTracing(t *testing.T) {
+	var traceparent string
+	srv := newTicketServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case protocol.PathBook:
+			traceparent = r.Header.Get("traceparent")
+			w.Header().Set("Trace-Id", "server-trace")
+			w.Header().Set("Server-Cost-Milliseconds", "42")
+			fmt.Fprint(w, `{"code":3001,"msg":"rate unavailable"}`)
+		case protocol.PathHotelList:
+			fmt.Fprint(w, `{"code":0,"data":{}}`)
+		}
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client, err := NewClient(WithBaseURL(srv.URL), WithCredentials("key", "secret"), WithTracerProvider(provider))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	req := testBookReq()
+	req.SessionId = "s1"
+	_, err = client.Book(context.Background(), req)
+	if _, ok := types.CastBizErr(err); !ok {
+		t.Fatalf("Expected BizError, got %v", err)
+	}
+
+	var book sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "hotelbyte.Book" {
+			book = span
+		}
+	}
+	if book == nil {
+		t.Fatal("Expected a hotelbyte.Book span")
+	}
+	if traceparent == "" || traceparent[3:35] != book.SpanContext().TraceID().String() {
+		t.Errorf("Expected traceparent of trace %s, got %q", book.SpanContext().TraceID(), traceparent)
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range book.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	want := map[attribute.Key]any{
+		AttrEndpoint:            protocol.PathBook,
+		AttrSessionID:           "s1",
+		AttrCustomerReferenceNo: "ref-1",
+		AttrServerTraceID:       "server-trace",
+		AttrServerCostMs:        int64(42),
+		AttrBizErrorCode:        int64(3001),
+	}
+	for k, v := range want {
+		if got := attrs[k].AsInterface(); got != v {
+			t.Errorf("Attribute %s = %v, want %v", k, got, v)
+		}
+	}
+
+	// A nil request has no attributes of its own
+	if _, err := client.HotelList(context.Background(), nil); err != nil {
+		t.Fatalf("HotelList failed: %v", err)
+	}
+	for _, span := range recorder.Ended() {
+		if span.Name() != "hotelbyte.HotelList" {
+			continue
+		}
+		for _, kv := range span.Attributes() {
+			if kv.Key == AttrHotelIDCount {
+				t.Errorf("Unexpected attribute %s of a nil request", kv.Key)
+			}
+		}
+	}
+}
@@ -2,13 +2,11 @@ package hotelbyte
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 
-	"github.com/sirupsen/logrus"
-
 	"github.com/bytedance/sonic"
 	"github.com/go-resty/resty/v2"
 	"github.com/hotelbyte-com/sdk-go/protocol/types"
@@ -16,8 +14,13 @@ import (
 
 // Transport represents HTTP transport layer
 type Transport struct {
-	client *resty.Client
-	config *Config
+	client      *resty.Client
+	config      *Config
+	handler     RoundTripFunc
+	reconcilers map[string]ReconcileFunc
+	rateLimit   *rateLimitTracker
+	limiter     *rateLimiter
+	breakers    *circuitBreakers
 }
 
 // NewTransport creates a new transport layer
@@ -28,29 +31,106 @@ func NewTransport(config *Config) (*Transport, error) {
 		SetHeader("User-Agent", config.HTTPConfig.UserAgent).
 		SetHeader("Content-Type", "application/json").
 		SetJSONMarshaler(sonic.Marshal).
-		SetJSONUnmarshaler(sonic.Unmarshal).
-		SetTransport(&http.Transport{
+		SetJSONUnmarshaler(sonic.Unmarshal)
+	if config.HTTPConfig.Transport != nil {
+		client.SetTransport(config.HTTPConfig.Transport)
+	} else {
+		client.SetTransport(&http.Transport{
 			Proxy:               http.ProxyFromEnvironment,
 			MaxIdleConns:        config.HTTPConfig.MaxIdleConns,
 			MaxIdleConnsPerHost: config.HTTPConfig.MaxConnsPerHost,
 			IdleConnTimeout:     90 * time.Second,
-		}).
-		SetRetryCount(config.RetryConfig.MaxRetries).
-		SetRetryWaitTime(config.RetryConfig.InitialDelay).
-		SetRetryMaxWaitTime(config.RetryConfig.MaxDelay).
-		AddRetryCondition(func(r *resty.Response, err error) bool {
-			// 重试条件：网络错误 || 429 Too Many Requests || 5xx Server Error
-			return err != nil || r.StatusCode() == 429 || r.StatusCode() >= 500
 		})
+	}
 
-	return &Transport{
-		client: client,
-		config: config,
-	}, nil
+	t := &Transport{
+		client:      client,
+		config:      config,
+		reconcilers: make(map[string]ReconcileFunc),
+		rateLimit:   newRateLimitTracker(),
+		limiter:     newRateLimiter(config.RateLimitConfig),
+		breakers:    newCircuitBreakers(config.CircuitBreaker),
+	}
+
+	// Middlewares configured by the user wrap the default ones
+	middlewares := append([]Middleware(nil), config.Middlewares...)
+	if !config.DisableDefaultMiddlewares {
+		middlewares = append(middlewares, DefaultMiddlewares(config.Logging)...)
+	}
+	if config.Metrics != nil {
+		middlewares = append(middlewares, metricsMiddleware(config.Metrics))
+	}
+	if config.TracingConfig.TracerProvider != nil {
+		middlewares = append(middlewares, tracingMiddleware(config.TracingConfig.propagator()))
+	}
+	t.handler = Chain(middlewares...)(t.roundTrip)
+
+	return t, nil
+}
+
+// SetReconciler sets the function checking whether a failed request to the path took effect anyway.
+// It runs whenever a request to the path fails with an unknown outcome, a transport error after
+// sending or a 5xx, before any retry.
+// It must be set before the transport is used.
+func (t *Transport) SetReconciler(path string, reconcile ReconcileFunc) {
+	t.reconcilers[path] = reconcile
 }
 
-// Do executes HTTP request
+// Do executes HTTP request through the middleware chain, retrying failures as allowed by
+// the RetryClass of the endpoint. Requests carrying test flags are refused on the production API.
 func (t *Transport) Do(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, error) {
+	if hasTestFlags(req) && isProduction(t.config.BaseURL) {
+		return nil, ErrTestFlagsInProduction
+	}
+	retryConfig := t.config.RetryConfig
+	class := retryConfig.classOf(req.Path)
+	if policy, ok := retryPolicyFromContext(ctx); ok {
+		class = policy.Class
+		retryConfig.MaxRetries = policy.MaxRetries
+	}
+	reconcile := t.reconcilers[req.Path]
+	breaker := t.breakers.get(req.Path)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.attempt(ctx, req, attempt, breaker)
+		if errors.Is(err, ErrCircuitOpen) {
+			return nil, err
+		}
+		// A request abandoned by the caller is not retried
+		if !isRetryableFailure(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		// If the outcome is unknown, check whether the request took effect before sending it again
+		if reconcile != nil && isUnknownOutcome(resp, err) {
+			if reconciled, ok := reconcile(ctx, req); ok {
+				return reconciled, nil
+			}
+		}
+		if attempt > retryConfig.MaxRetries || !class.allowsRetry(err) {
+			return resp, err
+		}
+
+		// Wait as long as the server asked on 429, but give up early if the caller can't wait that
+		// long, or if it is longer than MaxDelay: a shorter wait would only be throttled again
+		delay := retryConfig.backoff(attempt)
+		if d, ok := throttleDelay(resp, time.Now()); ok {
+			if retryConfig.MaxDelay > 0 && d > retryConfig.MaxDelay {
+				return resp, err
+			}
+			delay = d
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+		if sleep(ctx, delay) != nil {
+			return resp, err
+		}
+	}
+}
+
+// roundTrip sends the request over the wire
+func (t *Transport) roundTrip(ctx context.Context, req *types.HttpRequest) (*types.HttpResponse, error) {
 	// Build Resty request
 	r := t.client.R().SetContext(ctx)
 
@@ -69,25 +149,12 @@ func (t *Transport) Do(ctx context.Context, req *types.HttpRequest) (*types.Http
 		r.SetBody(req.Body)
 	}
 
-	// Execute request (Resty handles retry internally)
+	// Execute request (retries are handled by Do)
 	resp, err := r.Execute(req.Method, req.Path)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, &TransportError{Endpoint: req.Path, Err: err}
 	}
 
-	headers := resp.Header()
-	sb := strings.Builder{}
-	for _, key := range keys {
-		if val := headers.Get(key); val != "" {
-			sb.WriteString(key)
-			sb.WriteString("=")
-			sb.WriteString(val)
-			sb.WriteString(" ")
-		}
-	}
-	if sb.Len() > 0 {
-		logrus.WithContext(ctx).Infof("%s Response headers: %s", req.Path, strings.TrimSpace(sb.String()))
-	}
 	return &types.HttpResponse{
 		StatusCode: resp.StatusCode(),
 		Headers:    resp.Header(),
@@ -95,13 +162,52 @@ func (t *Transport) Do(ctx context.Context, req *types.HttpRequest) (*types.Http
 	}, nil
 }
 
-var (
-	keys = []string{
-		"Trace-Id",
-		"Session-Id",
-		"Server-Cost-Milliseconds",
+// attempt sends the request once, guarded by the circuit breaker and the rate limiter
+func (t *Transport) attempt(ctx context.Context, req *types.HttpRequest, attempt int, breaker *circuitBreaker) (*types.HttpResponse, error) {
+	var generation uint64
+	if breaker != nil {
+		var err error
+		if generation, err = breaker.allow(time.Now()); err != nil {
+			return nil, err
+		}
 	}
-)
+
+	// Every attempt, retries included, spends the client-side budget
+	if err := t.limiter.wait(ctx, req.Path); err != nil {
+		if breaker != nil {
+			breaker.abandon(generation)
+		}
+		return nil, fmt.Errorf("rate limit wait failed: %w", err)
+	}
+
+	countAttempt(ctx, req.Path)
+	resp, err := t.handler(withAttempt(ctx, attempt), req)
+	t.rateLimit.update(resp, time.Now())
+
+	if breaker != nil {
+		if ctx.Err() != nil {
+			breaker.abandon(generation)
+		} else {
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			breaker.record(generation, isBreakerFailure(statusCode, err), time.Now())
+		}
+	}
+	return resp, err
+}
+
+// CircuitStates returns the circuit breaker state of every endpoint called so far.
+// It is empty if no circuit breaker is configured.
+func (t *Transport) CircuitStates() map[string]CircuitState {
+	return t.breakers.States()
+}
+
+// RateLimitStatus returns the rate limit of the account as last reported by the server
+func (t *Transport) RateLimitStatus() RateLimitStatus {
+	return t.rateLimit.Status()
+}
 
 // Close closes the transport layer
 func (t *Transport) Close() error {
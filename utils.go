@@ -2,9 +2,12 @@ package hotelbyte
 
 import (
 	"github.com/bytedance/sonic"
-	"log"
 )
 
+// ToJSON marshals v to a JSON string, returning "" if it cannot be marshaled.
+//
+// Deprecated: ToJSON keeps the secrets and PII fields as they are; use ToRedactedJSON for logs,
+// errors and support bundles.
 func ToJSON(v interface{}) string {
 	if s, ok := v.(string); ok {
 		return s
@@ -14,7 +17,6 @@ func ToJSON(v interface{}) string {
 	}
 	s, err := sonic.MarshalString(v)
 	if err != nil {
-		log.Printf("ToJSONString failed(%v) from value(%v)\n", err, v)
 		return ""
 	}
 	return s